@@ -0,0 +1,49 @@
+package structvalidator
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ValidatorFunc is a custom validator used with "custom:name" tag.  It returns false and Fail* flag when value is
+// invalid.  When returned flag is 0, FailCustom is used.
+type ValidatorFunc func(value reflect.Value) (ok bool, failFlag int64)
+
+var validators = map[string]ValidatorFunc{}
+var validatorsMu sync.RWMutex
+
+// RegisterValidator registers a custom validator globally, so it can be used with "custom:name" tag.  Validators in
+// ValidationOptions take precedence over the ones registered globally.
+func RegisterValidator(name string, fn ValidatorFunc) {
+	validatorsMu.Lock()
+	defer validatorsMu.Unlock()
+	validators[name] = fn
+}
+
+// runCustomValidators runs custom validators and returns the ones that failed, named "custom:name".  An error is
+// returned when validator has not been registered.
+func runCustomValidators(names []string, value reflect.Value, optionsValidators map[string]ValidatorFunc) (failures, error) {
+	var f failures
+	for _, name := range names {
+		fn, ok := optionsValidators[name]
+		if !ok {
+			validatorsMu.RLock()
+			fn, ok = validators[name]
+			validatorsMu.RUnlock()
+		}
+		if !ok {
+			return nil, fmt.Errorf("validator '%s' has not been registered", name)
+		}
+
+		valid, failFlag := fn(value)
+		if valid {
+			continue
+		}
+		if failFlag == 0 {
+			failFlag = FailCustom
+		}
+		f.add(failFlag, "custom:"+name)
+	}
+	return f, nil
+}
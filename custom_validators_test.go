@@ -0,0 +1,57 @@
+package structvalidator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type Test23 struct {
+	ISBN string `validation:"req custom:isbn"`
+	SKU  string `validation:"custom:sku"`
+}
+
+type Test24 struct {
+	Code string `validation:"custom:notregistered"`
+}
+
+func TestCustomValidators(t *testing.T) {
+	RegisterValidator("isbn", func(value reflect.Value) (bool, int64) {
+		return len(strings.Replace(value.String(), "-", "", -1)) == 13, 0
+	})
+	opts := &ValidationOptions{
+		Validators: map[string]ValidatorFunc{
+			"sku": func(value reflect.Value) (bool, int64) {
+				if !strings.HasPrefix(value.String(), "SKU") {
+					return false, FailRegexp
+				}
+				return true, 0
+			},
+		},
+	}
+
+	s := Test23{
+		ISBN: "978-3-16-148410",
+		SKU:  "123",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"ISBN": FailCustom,
+		"SKU":  FailRegexp,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s = Test23{
+		ISBN: "978-3-16-148410-0",
+		SKU:  "SKU123",
+	}
+	compare(&s, true, map[string]int64{}, opts, t)
+}
+
+func TestCustomValidatorNotRegistered(t *testing.T) {
+	s := Test24{}
+	_, _, err := ValidateE(&s, &ValidationOptions{})
+	if err == nil || !strings.Contains(err.Error(), "notregistered") {
+		t.Fatalf("ValidateE did not return an error for custom validator that has not been registered")
+	}
+}
@@ -0,0 +1,194 @@
+package structvalidator
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// FieldError contains details of a field that failed validation:
+// * Field is a key of the field, the same as in map returned by Validate
+// * Value is the value that was validated, it is nil when value cannot be obtained (eg. unexported field)
+// * Flags is a bitwise OR of Fail* constants
+// * Rules contains names of the rules (tags) that failed, eg. "lenmin" or "email"
+// For map entries, names of the rules that failed for the key are prefixed with "key:", eg. "key:lenmax", and Value is
+// the key when only the key failed.
+type FieldError struct {
+	Field string
+	Value interface{}
+	Flags int64
+	Rules []string
+
+	validation *ValueValidation
+	// keyValidation contains rules for keys of a map, when the field is a map entry
+	keyValidation *ValueValidation
+	failures      failures
+}
+
+// ValidateDetailed works the same as Validate but returns a slice of FieldError with details of each field that
+// failed validation, in order of struct fields.  Similarly to Validate, func panics when validation tags are invalid.
+func ValidateDetailed(obj interface{}, options *ValidationOptions) (bool, []FieldError) {
+	valid, fieldErrors, err := validate(obj, options)
+	if err != nil {
+		panic(err.Error())
+	}
+	return valid, fieldErrors
+}
+
+// ValidateRules works the same as Validate but returns names of the failed rules (tags) for each field, instead of
+// failure flags, eg. []string{"lenmin", "regexp"}, so that it is clear which rule failed when a field has many of
+// them.  Similarly to Validate, func panics when validation tags are invalid.
+func ValidateRules(obj interface{}, options *ValidationOptions) (bool, map[string][]string) {
+	valid, fieldErrors := ValidateDetailed(obj, options)
+
+	invalidFields := make(map[string][]string, len(fieldErrors))
+	for _, fieldError := range fieldErrors {
+		invalidFields[fieldError.Field] = fieldError.Rules
+	}
+	return valid, invalidFields
+}
+
+// FieldFailure contains key of a field that failed validation and a bitwise OR of Fail* constants
+type FieldFailure struct {
+	Field string
+	Flags int64
+}
+
+// ValidateOrdered works the same as Validate but returns a slice of FieldFailure in order of struct fields, instead of
+// a map, so that the result is deterministic.  Struct-level failure is the last one.  Similarly to Validate, func
+// panics when validation tags are invalid.
+func ValidateOrdered(obj interface{}, options *ValidationOptions) (bool, []FieldFailure) {
+	valid, fieldErrors := ValidateDetailed(obj, options)
+
+	failures := make([]FieldFailure, 0, len(fieldErrors))
+	for _, fieldError := range fieldErrors {
+		failures = append(failures, FieldFailure{
+			Field: fieldError.Field,
+			Flags: fieldError.Flags,
+		})
+	}
+	return valid, failures
+}
+
+// MustValidate works the same as Validate but panics when the struct is invalid, with a message listing failed fields
+// and names of their rules, eg. "validation failed: LastName (lenmin), Email (req)".  It is meant for cases where
+// invalid value is a bug, eg. setting up tests, and not for validating user input.
+func MustValidate(obj interface{}, options *ValidationOptions) {
+	valid, fieldErrors := ValidateDetailed(obj, options)
+	if valid {
+		return
+	}
+
+	failures := make([]string, 0, len(fieldErrors))
+	for _, fieldError := range fieldErrors {
+		failures = append(failures, fieldError.Field+" ("+strings.Join(fieldError.Rules, ", ")+")")
+	}
+	panic("validation failed: " + strings.Join(failures, ", "))
+}
+
+func newFieldError(field string, value reflect.Value, f failures, validation *ValueValidation) FieldError {
+	fieldError := FieldError{
+		Field:      field,
+		Flags:      f.flags(),
+		Rules:      f.names(),
+		validation: validation,
+		failures:   f,
+	}
+	if value.IsValid() && value.CanInterface() {
+		fieldError.Value = value.Interface()
+	}
+	return fieldError
+}
+
+// keyRulePrefix is prepended to names of the rules that failed for a key of a map entry
+const keyRulePrefix = "key:"
+
+// failedRule is a rule that failed, with its Fail* flag and name, eg. "uuid:v4" with FailUUID
+type failedRule struct {
+	flag int64
+	name string
+	// elements is set when a length rule failed for the number of elements of a slice, an array or a map
+	elements bool
+}
+
+// failures contains rules that failed for a value.  Names are set where a rule fails, as many rules can share the
+// same flag, eg. "positive" and "valmin".
+type failures []failedRule
+
+// add appends a failed rule, unless it is already there, eg. when many substrings of "contains" are missing
+func (f *failures) add(flag int64, name string) {
+	f.addRule(failedRule{flag: flag, name: name})
+}
+
+// addRule works the same as add but takes the whole failed rule
+func (f *failures) addRule(rule failedRule) {
+	for _, r := range *f {
+		if r.flag == rule.flag && r.name == rule.name {
+			return
+		}
+	}
+	*f = append(*f, rule)
+}
+
+// append adds all the failed rules from other
+func (f *failures) append(other failures) {
+	for _, r := range other {
+		f.addRule(r)
+	}
+}
+
+// isMissing checks if a required rule failed, which means that value is missing and rules that refer to other fields,
+// custom validators and allowed values are not checked, whatever the flag is, eg. FailZero or FailTime
+func (f failures) isMissing() bool {
+	for _, r := range f {
+		if r.name == "req" || r.name == "reqtrim" || r.name == "notblank" {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutKeyRules returns failed rules except the ones for a key of a map entry
+func (f failures) withoutKeyRules() failures {
+	var rules failures
+	for _, r := range f {
+		if !strings.HasPrefix(r.name, keyRulePrefix) {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+// flags returns a bitwise OR of Fail* flags of the failed rules
+func (f failures) flags() int64 {
+	var flags int64
+	for _, r := range f {
+		flags = flags | r.flag
+	}
+	return flags
+}
+
+// names returns names of the failed rules, ordered by their flags, the same as messages are
+func (f failures) names() []string {
+	names := make([]string, 0, len(f))
+	for _, r := range f.sorted() {
+		names = append(names, r.name)
+	}
+	return names
+}
+
+// sorted returns a copy of failures ordered by their flags, keeping the order of rules with the same flag
+func (f failures) sorted() failures {
+	s := make(failures, len(f))
+	copy(s, f)
+	sort.SliceStable(s, func(i, j int) bool { return s[i].flag < s[j].flag })
+	return s
+}
+
+// first returns the first failed rule only, in the same order as messages are returned, for FailFast option
+func (f failures) first() failures {
+	if len(f) == 0 {
+		return f
+	}
+	return f.sorted()[:1]
+}
@@ -0,0 +1,186 @@
+package structvalidator
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestValidateDetailed(t *testing.T) {
+	s := Test1{
+		FirstName:     "Johnny",
+		LastName:      "b",
+		Age:           15,
+		PostCode:      "43-155",
+		Email:         "john@example.com",
+		BelowZero:     -4,
+		DiscountPrice: 8000,
+		Country:       "GB",
+	}
+	expectedFieldErrors := []FieldError{
+		{Field: "LastName", Value: "b", Flags: FailLenMin, Rules: []string{"lenmin"}},
+		{Field: "Age", Value: 15, Flags: FailValMin, Rules: []string{"valmin"}},
+		{Field: "Email", Value: "invalidEmail", Flags: FailLenMax | FailEmail, Rules: []string{"lenmax", "email"}},
+	}
+	valid, fieldErrors := ValidateDetailed(&s, &ValidationOptions{
+		OverwriteFieldTags: map[string]map[string]string{
+			"Email": map[string]string{
+				"validation": "req email lenmax:3",
+			},
+		},
+		OverwriteFieldValues: map[string]interface{}{
+			"Email": "invalidEmail",
+		},
+	})
+	if valid {
+		t.Fatalf("ValidateDetailed returned invalid boolean value")
+	}
+	if len(fieldErrors) != len(expectedFieldErrors) {
+		t.Fatalf("ValidateDetailed returned invalid number of failed fields %d where it should be %d", len(fieldErrors), len(expectedFieldErrors))
+	}
+	for i, expected := range expectedFieldErrors {
+		fieldError := fieldErrors[i]
+		if fieldError.Field != expected.Field || fieldError.Value != expected.Value || fieldError.Flags != expected.Flags {
+			t.Fatalf("ValidateDetailed returned invalid field error %v where it should be %v", fieldError, expected)
+		}
+		if len(fieldError.Rules) != len(expected.Rules) {
+			t.Fatalf("ValidateDetailed returned invalid rules %v where it should be %v for %s", fieldError.Rules, expected.Rules, expected.Field)
+		}
+		for j := range expected.Rules {
+			if fieldError.Rules[j] != expected.Rules[j] {
+				t.Fatalf("ValidateDetailed returned invalid rules %v where it should be %v for %s", fieldError.Rules, expected.Rules, expected.Field)
+			}
+		}
+	}
+}
+
+func TestValidateRules(t *testing.T) {
+	s := Test1{
+		FirstName:     "Johnny",
+		LastName:      "b",
+		Age:           30,
+		PostCode:      "43155",
+		Email:         "john@example.com",
+		BelowZero:     -4,
+		DiscountPrice: 8000,
+		Country:       "GB",
+	}
+	expectedRules := map[string][]string{
+		"LastName": {"lenmin"},
+		"PostCode": {"lenmax", "regexp"},
+	}
+	valid, rules := ValidateRules(&s, &ValidationOptions{
+		OverwriteFieldTags: map[string]map[string]string{
+			"PostCode": map[string]string{
+				"validation": "req lenmax:3",
+			},
+		},
+	})
+	if valid {
+		t.Fatalf("ValidateRules returned invalid boolean value")
+	}
+	if !reflect.DeepEqual(rules, expectedRules) {
+		t.Fatalf("ValidateRules returned %v where it should be %v", rules, expectedRules)
+	}
+}
+
+func TestValidateRulesNamesFailedRule(t *testing.T) {
+	type Test struct {
+		Start    int
+		Min      int
+		Deadline time.Time `validation:"req after:now"`
+		End      int       `validation:"gtfield:Start gtefield:Min"`
+		Count    int       `validation:"positive valmin:5"`
+	}
+	s := Test{Start: 10, Min: 20, End: 15, Count: 3}
+
+	expectedRules := map[string][]string{
+		"Deadline": {"req"},
+		"End":      {"gtefield"},
+		"Count":    {"valmin"},
+	}
+	valid, rules := ValidateRules(&s, nil)
+	if valid {
+		t.Fatalf("ValidateRules returned invalid boolean value")
+	}
+	if !reflect.DeepEqual(rules, expectedRules) {
+		t.Fatalf("ValidateRules returned %v where it should be %v", rules, expectedRules)
+	}
+}
+
+func TestValidateOrdered(t *testing.T) {
+	s := Test1{
+		FirstName: "Jo",
+		LastName:  "b",
+		Age:       15,
+		PostCode:  "43-155",
+		Email:     "invalidEmail",
+		BelowZero: -4,
+		Country:   "GB",
+	}
+	expectedFailures := []FieldFailure{
+		{Field: "FirstName", Flags: FailLenMin},
+		{Field: "LastName", Flags: FailLenMin},
+		{Field: "Age", Flags: FailValMin},
+		{Field: "Email", Flags: FailEmail},
+		{Field: StructKey, Flags: FailFieldsMin},
+	}
+	opts := &ValidationOptions{
+		SetFieldsCount: []SetFieldsCount{
+			{Fields: []string{"DiscountPrice", "County"}, Min: 1},
+		},
+	}
+
+	// order is the same every time
+	for j := 0; j < 10; j++ {
+		valid, failures := ValidateOrdered(&s, opts)
+		if valid {
+			t.Fatalf("ValidateOrdered returned invalid boolean value")
+		}
+		if !reflect.DeepEqual(failures, expectedFailures) {
+			t.Fatalf("ValidateOrdered returned %v where it should be %v", failures, expectedFailures)
+		}
+	}
+}
+
+func TestValidateDetailedWithNotBlank(t *testing.T) {
+	s := struct {
+		Name string `validation:"notblank"`
+	}{Name: " "}
+	_, fieldErrors := ValidateDetailed(&s, nil)
+	if len(fieldErrors) != 1 || !reflect.DeepEqual(fieldErrors[0].Rules, []string{"notblank"}) {
+		t.Fatalf("ValidateDetailed returned invalid rules for notblank")
+	}
+}
+
+func TestMustValidate(t *testing.T) {
+	s := Test1{
+		FirstName:     "Johnny",
+		LastName:      "b",
+		Age:           18,
+		PostCode:      "43-155",
+		Email:         "john@",
+		BelowZero:     -4,
+		DiscountPrice: 8000,
+		Country:       "GB",
+	}
+	expectedMessage := "validation failed: LastName (lenmin), Email (email)"
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatalf("MustValidate did not panic")
+		}
+		if r != expectedMessage {
+			t.Fatalf("MustValidate panicked with '%v' where it should be '%s'", r, expectedMessage)
+		}
+	}()
+
+	s.LastName = "Smith"
+	s.Email = "john@example.com"
+	MustValidate(&s, nil)
+
+	s.LastName = "b"
+	s.Email = "john@"
+	MustValidate(&s, nil)
+}
@@ -12,6 +12,7 @@ const TypeText = 1
 const TypeTextarea = 2
 const TypePassword = 3
 const TypeEmail = 4
+const TypeURL = 5
 
 // Optional configuration for validation:
 // * RestrictFields defines what struct fields should be generated
@@ -79,9 +80,12 @@ func GenerateHTML(obj interface{}, options *HTMLOptions) map[string]string {
 			if fieldKind == reflect.String {
 				value = elem.Field(j).String()
 			}
-			if isInt(fieldKind) {
+			if isSignedInt(fieldKind) {
 				value = fmt.Sprintf("%d", elem.Field(j).Int())
 			}
+			if isUint(fieldKind) {
+				value = fmt.Sprintf("%d", elem.Field(j).Uint())
+			}
 		}
 
 		if options != nil && len(options.OverwriteValues) > 0 && options.OverwriteValues[field.Name] != "" {
@@ -129,6 +133,9 @@ func GenerateHTML(obj interface{}, options *HTMLOptions) map[string]string {
 			if strings.HasSuffix(field.Name, "Email") {
 				inputType = TypeEmail
 			}
+			if strings.HasSuffix(field.Name, "URL") {
+				inputType = TypeURL
+			}
 			// Price not supported here yet
 		}
 
@@ -151,6 +158,9 @@ func GenerateHTML(obj interface{}, options *HTMLOptions) map[string]string {
 			if inputType == TypeEmail {
 				fieldTypeAttr = ` type="email"`
 			}
+			if inputType == TypeURL {
+				fieldTypeAttr = ` type="url"`
+			}
 			if inputType == TypePassword {
 				fieldTypeAttr = ` type="password"`
 				fieldValue = ""
@@ -176,13 +186,17 @@ func getHTMLAttributesFromTag(tag string) (string, int) {
 			inputType = TypeEmail
 			continue
 		}
+		if opt == "url" {
+			inputType = TypeURL
+			continue
+		}
 		if opt == "uitextarea" {
 			inputType = TypeTextarea
 		}
 		if opt == "uipassword" {
 			inputType = TypePassword
 		}
-		for _, valOpt := range []string{"lenmin", "lenmax", "valmin", "valmax", "regexp"} {
+		for _, valOpt := range []string{"lenmin", "lenmax", "len", "valmin", "valmax", "regexp"} {
 			if strings.HasPrefix(opt, valOpt+":") {
 				val := strings.Replace(opt, valOpt+":", "", 1)
 				if valOpt == "regexp" {
@@ -199,6 +213,8 @@ func getHTMLAttributesFromTag(tag string) (string, int) {
 					attrs = attrs + fmt.Sprintf(` minlength="%d"`, i)
 				case "lenmax":
 					attrs = attrs + fmt.Sprintf(` maxlength="%d"`, i)
+				case "len":
+					attrs = attrs + fmt.Sprintf(` minlength="%d" maxlength="%d"`, i, i)
 				case "valmin":
 					attrs = attrs + fmt.Sprintf(` min="%d"`, i)
 				case "valmax":
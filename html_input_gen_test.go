@@ -110,3 +110,17 @@ func TestGenerateHTMLWithFieldValues(t *testing.T) {
 		t.Fatal("GenerateHTML failed to output HTML for 'Email' field")
 	}
 }
+
+func TestGenerateHTMLWithURL(t *testing.T) {
+	s := &Test15{}
+	fieldsHTMLInputs := GenerateHTML(s, &HTMLOptions{
+		ValidateWhenSuffix: true,
+	})
+
+	if fieldsHTMLInputs["Website"] != `<input type="url" name="Website" required/>` {
+		t.Fatal("GenerateHTML failed to output HTML for 'Website' field")
+	}
+	if fieldsHTMLInputs["HomepageURL"] != `<input type="url" name="HomepageURL"/>` {
+		t.Fatal("GenerateHTML failed to output HTML for 'HomepageURL' field")
+	}
+}
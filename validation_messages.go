@@ -0,0 +1,233 @@
+package structvalidator
+
+import (
+	"strconv"
+	"strings"
+)
+
+// BoundPlaceholder is replaced with the configured bound (eg. LenMin) of a rule in a failure message template
+const BoundPlaceholder = "{bound}"
+
+// DefaultFailureMessages contains default message templates for each of the Fail* constants
+var DefaultFailureMessages = map[int64]string{
+	FailLenMin:       "must be at least " + BoundPlaceholder + " characters",
+	FailLenMax:       "must be at most " + BoundPlaceholder + " characters",
+	FailValMin:       "must be at least " + BoundPlaceholder,
+	FailValMax:       "must be at most " + BoundPlaceholder,
+	FailEmpty:        "is required",
+	FailRegexp:       "does not match the required pattern",
+	FailEmail:        "is not a valid email",
+	FailZero:         "is required",
+	FailBool:         "must be " + BoundPlaceholder,
+	FailNil:          "is required",
+	FailLen:          "must be exactly " + BoundPlaceholder + " characters",
+	FailOneOf:        "must be one of: " + BoundPlaceholder,
+	FailEqField:      "must be equal to " + BoundPlaceholder,
+	FailURL:          "is not a valid URL",
+	FailUUID:         "is not a valid UUID",
+	FailAlpha:        "must contain only letters",
+	FailNumeric:      "must contain only digits",
+	FailAlphanumeric: "must contain only letters and digits",
+	FailTime:         "is not a valid time",
+	FailCustom:       "is not valid",
+	FailRegexpNot:    "matches a forbidden pattern",
+	FailLowercase:    "must be lowercase",
+	FailUppercase:    "must be uppercase",
+	FailPrefix:       "must start with " + BoundPlaceholder,
+	FailSuffix:       "must end with " + BoundPlaceholder,
+	FailFieldsMin:    "has too few fields set",
+	FailFieldsMax:    "has too many fields set",
+	FailNumber:       "is not a valid number",
+	FailContains:     "must contain " + BoundPlaceholder,
+	FailExcludes:     "cannot contain " + BoundPlaceholder,
+	FailGtField:      "must be greater than " + BoundPlaceholder,
+	FailLtField:      "must be less than " + BoundPlaceholder,
+	FailIP:           "is not a valid IP address",
+	FailCIDR:         "is not a valid CIDR",
+	FailDigits:       "must have " + BoundPlaceholder + " digits",
+	FailBase64:       "is not valid base64",
+	FailHex:          "is not valid hex",
+	FailDecimal:      "must be a decimal number with at most " + BoundPlaceholder + " decimal places",
+	FailJSON:         "is not valid JSON",
+	FailDateTime:     "must be a date in format " + BoundPlaceholder,
+	FailWithinField:  "must be within " + BoundPlaceholder,
+	FailCreditCard:   "is not a valid credit card number",
+	FailHostname:     "is not a valid hostname",
+	FailFQDN:         "is not a valid fully qualified domain name",
+	FailMultipleOf:   "must be a multiple of " + BoundPlaceholder,
+	FailRanges:       "must be in one of the ranges: " + BoundPlaceholder,
+	FailCharset:      "must contain only characters: " + BoundPlaceholder,
+	FailCharsetNot:   "cannot contain characters: " + BoundPlaceholder,
+	FailGt:           "must be greater than " + BoundPlaceholder,
+	FailLt:           "must be less than " + BoundPlaceholder,
+	FailSemVer:       "is not a valid semantic version",
+	FailType:         "has invalid type",
+}
+
+// elementsFailureMessages contains default message templates for length rules of slices, arrays and maps, which
+// count elements instead of characters, and it takes precedence over DefaultFailureMessages
+var elementsFailureMessages = map[int64]string{
+	FailLenMin: "must have at least " + BoundPlaceholder + " elements",
+	FailLenMax: "must have at most " + BoundPlaceholder + " elements",
+	FailLen:    "must have exactly " + BoundPlaceholder + " elements",
+}
+
+// ruleFailureMessages contains default message templates for rules that share their Fail* constant with other rules,
+// and it takes precedence over DefaultFailureMessages
+var ruleFailureMessages = map[string]string{
+	"positive":    "must be greater than 0",
+	"nonneg":      "must be at least 0",
+	"negative":    "must be less than 0",
+	"nonpositive": "must be at most 0",
+	"gte":         "must be greater than or equal to " + BoundPlaceholder,
+	"lte":         "must be less than or equal to " + BoundPlaceholder,
+	"gtefield":    "must be greater than or equal to " + BoundPlaceholder,
+	"ltefield":    "must be less than or equal to " + BoundPlaceholder,
+}
+
+// ValidateWithMessages works the same as Validate but instead of a map of failure flags, it returns a map with slice
+// of human-readable messages for each field that failed validation.  Message templates can be overwritten with
+// FailureMessages in ValidationOptions, or replaced with a single message for a field with "validation_msg" tag.
+// Similarly to Validate, func panics when validation tags are invalid.
+func ValidateWithMessages(obj interface{}, options *ValidationOptions) (bool, map[string][]string) {
+	valid, fieldErrors, err := validate(obj, options)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	var templates map[int64]string
+	if options != nil {
+		templates = options.FailureMessages
+	}
+
+	messages := make(map[string][]string, len(fieldErrors))
+	for _, fieldError := range fieldErrors {
+		messages[fieldError.Field] = getFailureMessages(fieldError.failures, fieldError.validation, fieldError.keyValidation, templates)
+	}
+
+	return valid, messages
+}
+
+// getFailureMessages returns a message for each failed rule, in order of their flags.  Template from options is used
+// first, then the default one for the rule, and then the default one for its flag.  Rules that failed for a key of a map
+// entry get their bounds from keys, which are the rules for map keys.
+func getFailureMessages(f failures, v *ValueValidation, keys *ValueValidation, templates map[int64]string) []string {
+	if v != nil && v.Message != "" {
+		return []string{v.Message}
+	}
+
+	messages := []string{}
+	for _, r := range f.sorted() {
+		name, rv := r.name, v
+		if strings.HasPrefix(name, keyRulePrefix) {
+			name, rv = strings.TrimPrefix(name, keyRulePrefix), keys
+		}
+		tpl, ok := templates[r.flag]
+		if !ok && r.elements {
+			tpl, ok = elementsFailureMessages[r.flag]
+		}
+		if !ok {
+			tpl, ok = ruleFailureMessages[name]
+		}
+		if !ok {
+			tpl = DefaultFailureMessages[r.flag]
+		}
+		messages = append(messages, strings.Replace(tpl, BoundPlaceholder, getFailureBound(name, rv), -1))
+	}
+	return messages
+}
+
+// getFailureBound returns the configured bound of a rule, eg. "5" for "lenmin:5"
+func getFailureBound(rule string, v *ValueValidation) string {
+	if v == nil {
+		return ""
+	}
+
+	switch rule {
+	case "lenmin":
+		return strconv.Itoa(v.LenMin)
+	case "lenmax":
+		return strconv.Itoa(v.LenMax)
+	case "len":
+		return strconv.Itoa(v.Len)
+	case "valmin":
+		if v.ValMin == 0 && v.ValMinUint != 0 {
+			return strconv.FormatUint(v.ValMinUint, 10)
+		}
+		if v.ValMin != 0 || v.ValMinFloat == 0 {
+			return strconv.FormatInt(v.ValMin, 10)
+		}
+		return strconv.FormatFloat(v.ValMinFloat, 'f', -1, 64)
+	case "valmax":
+		if v.ValMax == 0 && v.ValMaxUint != 0 {
+			return strconv.FormatUint(v.ValMaxUint, 10)
+		}
+		if v.ValMax != 0 || v.ValMaxFloat == 0 {
+			return strconv.FormatInt(v.ValMax, 10)
+		}
+		return strconv.FormatFloat(v.ValMaxFloat, 'f', -1, 64)
+	case "oneof", "oneofci":
+		return strings.Join(v.OneOf, ", ")
+	case "oneofint":
+		allowed := make([]string, 0, len(v.OneOfInt))
+		for _, i := range v.OneOfInt {
+			allowed = append(allowed, strconv.FormatInt(i, 10))
+		}
+		return strings.Join(allowed, ", ")
+	case "prefix":
+		return v.Prefix
+	case "suffix":
+		return v.Suffix
+	case "contains":
+		return strings.Join(v.Contains, ", ")
+	case "excludes":
+		return strings.Join(v.Excludes, ", ")
+	case "decimal":
+		return strconv.Itoa(v.Decimal)
+	case "datetime":
+		return v.DateTime
+	case "gt", "gte":
+		return strconv.FormatFloat(v.Gt, 'f', -1, 64)
+	case "lt", "lte":
+		return strconv.FormatFloat(v.Lt, 'f', -1, 64)
+	case "charset":
+		return v.Charset
+	case "charsetnot":
+		return v.CharsetNot
+	case "multipleof":
+		return strconv.FormatFloat(v.MultipleOf, 'f', -1, 64)
+	case "ranges":
+		ranges := make([]string, 0, len(v.Ranges))
+		for _, r := range v.Ranges {
+			ranges = append(ranges, strconv.FormatInt(r[0], 10)+"-"+strconv.FormatInt(r[1], 10))
+		}
+		return strings.Join(ranges, ", ")
+	case "withinfield":
+		return strconv.FormatFloat(v.WithinDelta, 'f', -1, 64) + " of " + v.WithinField
+	case "digits":
+		return "exactly " + strconv.Itoa(v.Digits)
+	case "digitsmin", "digitsmax":
+		switch {
+		case v.DigitsMin > -1 && v.DigitsMax > -1:
+			return "between " + strconv.Itoa(v.DigitsMin) + " and " + strconv.Itoa(v.DigitsMax)
+		case v.DigitsMin > -1:
+			return "at least " + strconv.Itoa(v.DigitsMin)
+		}
+		return "at most " + strconv.Itoa(v.DigitsMax)
+	case "eqfield":
+		return v.EqField
+	case "gtfield":
+		return v.GtField
+	case "gtefield":
+		return v.GteField
+	case "ltfield":
+		return v.LtField
+	case "ltefield":
+		return v.LteField
+	case "istrue", "req", "reqtrim", "notblank":
+		return "true"
+	case "isfalse":
+		return "false"
+	}
+	return ""
+}
@@ -0,0 +1,233 @@
+package structvalidator
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestValidateWithMessages(t *testing.T) {
+	s := Test1{
+		FirstName:     "123456789012345678901234567890",
+		LastName:      "b",
+		Age:           15,
+		Price:         0,
+		PostCode:      "AA123",
+		Email:         "invalidEmail",
+		BelowZero:     8,
+		DiscountPrice: 9999,
+		Country:       "Tokelau",
+		County:        "",
+	}
+	expectedMessages := map[string][]string{
+		"FirstName":     {"must be at most 25 characters"},
+		"LastName":      {"must be at least 2 characters"},
+		"Age":           {"must be at least 18"},
+		"PostCode":      {"does not match the required pattern"},
+		"Email":         {"is not a valid email"},
+		"BelowZero":     {"must be at most -2"},
+		"DiscountPrice": {"must be at most 8000"},
+		"Country":       {"does not match the required pattern"},
+	}
+	valid, messages := ValidateWithMessages(&s, &ValidationOptions{})
+	if valid {
+		t.Fatalf("ValidateWithMessages returned invalid boolean value")
+	}
+	compareMessages(messages, expectedMessages, t)
+}
+
+func TestValidateWithMessagesForDigits(t *testing.T) {
+	s := Test39{
+		Pin:     1,
+		Account: 1,
+		Offset:  100,
+	}
+	expectedMessages := map[string][]string{
+		"Pin":     {"must have exactly 4 digits"},
+		"Account": {"must have between 6 and 12 digits"},
+		"Offset":  {"must have at most 2 digits"},
+	}
+	_, messages := ValidateWithMessages(&s, nil)
+	compareMessages(messages, expectedMessages, t)
+}
+
+func TestValidateWithMessagesWithOverwrittenTemplates(t *testing.T) {
+	s := Test5{
+		Price:    0.5,
+		Discount: 0.75,
+		Weight:   1,
+	}
+	expectedMessages := map[string][]string{
+		"Price":    {"cannot be lower than 1"},
+		"Discount": {"must be at most 0.5"},
+	}
+	valid, messages := ValidateWithMessages(&s, &ValidationOptions{
+		RestrictFields: map[string]bool{
+			"Price":    true,
+			"Discount": true,
+		},
+		FailureMessages: map[int64]string{
+			FailValMin: "cannot be lower than " + BoundPlaceholder,
+		},
+	})
+	if valid {
+		t.Fatalf("ValidateWithMessages returned invalid boolean value")
+	}
+	compareMessages(messages, expectedMessages, t)
+}
+
+func TestValidateWithMessagesWithLargeUintBound(t *testing.T) {
+	s := Test27{
+		Mask: math.MaxUint64,
+		Key:  math.MaxUint64,
+	}
+	expectedMessages := map[string][]string{
+		"Key": {"must be at most 18446744073709551614"},
+	}
+	_, messages := ValidateWithMessages(&s, nil)
+	compareMessages(messages, expectedMessages, t)
+}
+
+func compareMessages(messages map[string][]string, expectedMessages map[string][]string, t *testing.T) {
+	if len(messages) != len(expectedMessages) {
+		t.Fatalf("ValidateWithMessages returned invalid number of failed fields %d where it should be %d", len(messages), len(expectedMessages))
+	}
+	for k, v := range expectedMessages {
+		if len(messages[k]) != len(v) {
+			t.Fatalf("ValidateWithMessages returned invalid number of messages %d where it should be %d for %s", len(messages[k]), len(v), k)
+		}
+		for i := range v {
+			if messages[k][i] != v[i] {
+				t.Fatalf("ValidateWithMessages returned invalid message '%s' where it should be '%s' for %s", messages[k][i], v[i], k)
+			}
+		}
+	}
+}
+
+func TestValidateWithMessagesWithMessageTag(t *testing.T) {
+	s := struct {
+		WorkEmail string `validation:"req email lenmax:10" validation_msg:"Please enter a valid work email"`
+		Name      string `validation:"req" validation_msg:"Name is required"`
+		Phone     string `validation:"lenmin:5"`
+	}{
+		WorkEmail: "invalid-email",
+		Name:      "John",
+		Phone:     "123",
+	}
+	expectedMessages := map[string][]string{
+		"WorkEmail": {"Please enter a valid work email"},
+		"Phone":     {"must be at least 5 characters"},
+	}
+	_, messages := ValidateWithMessages(&s, nil)
+	compareMessages(messages, expectedMessages, t)
+
+	// message tag can be overwritten as other tags
+	_, messages = ValidateWithMessages(&s, &ValidationOptions{
+		OverwriteFieldTags: map[string]map[string]string{
+			"WorkEmail": {"validation_msg": "Zadejte platný e-mail"},
+		},
+	})
+	expectedMessages["WorkEmail"] = []string{"Zadejte platný e-mail"}
+	compareMessages(messages, expectedMessages, t)
+}
+
+func TestValidateWithMessagesForSignRules(t *testing.T) {
+	s := Test50{
+		Positive: 0,
+		Negative: 0,
+	}
+	expectedMessages := map[string][]string{
+		"Positive": {"must be greater than 0"},
+		"Negative": {"must be less than 0"},
+	}
+	_, messages := ValidateWithMessages(&s, nil)
+	compareMessages(messages, expectedMessages, t)
+}
+
+func TestValidateWithMessagesForCollections(t *testing.T) {
+	type Test struct {
+		Codes  []string          `validation:"lenmax:2"`
+		Labels map[string]string `validation:"lenmin:2"`
+		Sizes  [3]int            `validation:"len:2"`
+		Name   string            `validation:"lenmax:2"`
+	}
+	s := Test{
+		Codes:  []string{"a", "b", "c"},
+		Labels: map[string]string{"a": "b"},
+		Name:   "abc",
+	}
+	expectedMessages := map[string][]string{
+		"Codes":  {"must have at most 2 elements"},
+		"Labels": {"must have at least 2 elements"},
+		"Sizes":  {"must have exactly 2 elements"},
+		"Name":   {"must be at most 2 characters"},
+	}
+	_, messages := ValidateWithMessages(&s, nil)
+	compareMessages(messages, expectedMessages, t)
+}
+
+func TestValidateWithMessagesForMapKeys(t *testing.T) {
+	type Test struct {
+		Meta map[string]string `validation_keys:"lenmax:2" validation_values:"lenmax:5"`
+	}
+	s := Test{
+		Meta: map[string]string{"abcd": "ok", "ab": "abcdef", "abc": "abcdefg"},
+	}
+	expectedMessages := map[string][]string{
+		"Meta[abcd]": {"must be at most 2 characters"},
+		"Meta[ab]":   {"must be at most 5 characters"},
+		"Meta[abc]":  {"must be at most 2 characters", "must be at most 5 characters"},
+	}
+	_, messages := ValidateWithMessages(&s, nil)
+	compareMessages(messages, expectedMessages, t)
+
+	_, fieldErrors := ValidateDetailed(&s, nil)
+	expectedRules := map[string][]string{
+		"Meta[abcd]": {"key:lenmax"},
+		"Meta[ab]":   {"lenmax"},
+		"Meta[abc]":  {"key:lenmax", "lenmax"},
+	}
+	expectedValues := map[string]interface{}{
+		"Meta[abcd]": "abcd",
+		"Meta[ab]":   "abcdef",
+		"Meta[abc]":  "abcdefg",
+	}
+	if len(fieldErrors) != len(expectedRules) {
+		t.Fatalf("ValidateDetailed returned %v for map entries", fieldErrors)
+	}
+	for _, fieldError := range fieldErrors {
+		if !reflect.DeepEqual(fieldError.Rules, expectedRules[fieldError.Field]) || fieldError.Value != expectedValues[fieldError.Field] {
+			t.Fatalf("ValidateDetailed returned %v for %s where it should have %v rules and %v value", fieldError, fieldError.Field, expectedRules[fieldError.Field], expectedValues[fieldError.Field])
+		}
+	}
+}
+
+func TestValidateWithMessagesForDistinctFlags(t *testing.T) {
+	type Test struct {
+		Address string `validation:"ip hex"`
+		Score   int    `validation:"gt:10 valmin:5"`
+	}
+	s := Test{
+		Address: "host",
+		Score:   3,
+	}
+	expectedMessages := map[string][]string{
+		"Address": {"is not a valid IP address", "is not valid hex"},
+		"Score":   {"is too small", "must be greater than 10"},
+	}
+	opts := &ValidationOptions{
+		FailureMessages: map[int64]string{
+			FailValMin: "is too small",
+		},
+	}
+	_, messages := ValidateWithMessages(&s, opts)
+	compareMessages(messages, expectedMessages, t)
+
+	valid, fieldErrors := ValidateDetailed(&s, nil)
+	if valid || len(fieldErrors) != 2 {
+		t.Fatalf("ValidateDetailed returned %v and %v for fields failing many rules", valid, fieldErrors)
+	}
+	if fieldErrors[0].Flags != FailIP|FailHex || fieldErrors[0].Flags&FailRegexp > 0 {
+		t.Fatalf("ValidateDetailed returned %v for Address where it should be FailIP and FailHex", fieldErrors[0])
+	}
+}
@@ -1,10 +1,16 @@
 package structvalidator
 
 import (
+	"fmt"
+	"math"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unsafe"
 )
 
 // values for invalid field flags
@@ -18,107 +24,2190 @@ const (
 	FailRegexp
 	FailEmail
 	FailZero
+	FailBool
+	FailNil
+	FailLen
+	FailOneOf
+	FailEqField
+	FailURL
+	FailUUID
+	FailAlpha
+	FailNumeric
+	FailAlphanumeric
+	FailTime
+	FailCustom
+	FailRegexpNot
+	FailLowercase
+	FailUppercase
+	FailPrefix
+	FailSuffix
+	FailFieldsMin
+	FailFieldsMax
+	FailNumber
+	FailContains
+	FailExcludes
+	FailGtField
+	FailLtField
+	FailIP
+	FailCIDR
+	FailDigits
+	FailBase64
+	FailHex
+	FailDecimal
+	FailJSON
+	FailDateTime
+	FailWithinField
+	FailCreditCard
+	FailHostname
+	FailFQDN
+	FailMultipleOf
+	FailRanges
+	FailCharset
+	FailCharsetNot
+	FailGt
+	FailLt
+	FailSemVer
+	FailType
 )
 
+// StructKey is a key in the returned map for struct-level rules, eg. SetFieldsCount
+const StructKey = "_struct"
+
+// SetFieldsCount defines minimal and maximal number of fields, from the Fields, that have to be set (non-zero).
+// When Max is 0 then there is no maximum.
+type SetFieldsCount struct {
+	Fields []string
+	Min    int
+	Max    int
+}
+
+// CombinedLen defines maximal total length of string fields, from the Fields, eg. parts of a composite key
+type CombinedLen struct {
+	Fields []string
+	Max    int
+}
+
 // Optional configuration for validation:
-// * RestrictFields defines what struct fields should be validated
+// * RestrictFields defines what struct fields should be validated.  Key can be a path to a field of a nested struct,
+// eg. "Address.ZipCode", and then only this field of the nested struct is validated, while a key without a dot, eg.
+// "Address", makes the field and all of its nested fields validated.  For slices of structs, path does not contain
+// index, eg. "Items.Quantity"
+// * RestrictFieldIndexes defines what struct fields should be validated by their index in the struct that declares
+// them.  When it is set together with RestrictFields, fields listed in any of them are validated
+// * SkipFields defines fields that should not be validated (also from RestrictFields)
 // * OverwriteFieldTags can be used to overwrite tags for specific fields
 // * OverwriteTagName sets tag used to define validation (default is "validation")
 // * ValidateWhenSuffix will validate certain fields based on their name, eg. "PrimaryEmail" field will need to be a valid email
-// * OverwriteFieldValues is to use overwrite values for fields, so these values are validated not the ones in struct
+// and "DatabaseHost" a valid hostname
+// * SuffixRules contains tags, keyed by suffix, that are applied to fields which name ends with the suffix, in
+// addition to their own tags, eg. "Slug": "lowercase lenmax:64".  It is used only when ValidateWhenSuffix is set
+// * DisableBuiltinSuffixRules turns off rules for "Email", "URL", "Host" and "Price" suffixes when ValidateWhenSuffix
+// is set, so only SuffixRules are used, eg. when "Price" field can be negative or "DBHost" contains a port
+// * StrictTags makes validation return an error when a tag contains unknown rule, eg. misspelled "lenmn:3", instead
+// of ignoring it
+// * OmitEmpty makes fields that are not required and have zero value, eg. empty string or nil pointer, not validated,
+// the same as "omitempty" rule does for a single field.  It is useful for partial updates.
+// * OverwriteFieldValues is to use overwrite values for fields, so these values are validated not the ones in struct.
+// Value of a different type than the field is converted when it is safe, eg. int64 for int field, otherwise an error
+// is returned.  nil can be used for fields that can be nil, eg. pointers and slices, only
+// * UseTagNameInErrors sets tag (eg. "json") which value is used as a key in the returned map instead of field name
+// * FieldNameFunc returns name of a field, eg. in snake case, that is used as a key in the returned map and matched
+// with RestrictFields, instead of field name.  It takes precedence over UseTagNameInErrors, and other options, such
+// as SkipFields, still use field names
+// * Validators contains custom validators that can be used with "custom:name" tag, in addition to the ones registered
+// globally with RegisterValidator
+// * SetFieldsCount contains struct-level rules for number of fields that are set, eg. at least one of Email and Phone
+// has to be set - failure is returned with StructKey key
+// * FailureMessages overwrites message templates used by ValidateWithMessages, key is a Fail* constant
+// * ValidateUnexported makes values of unexported fields fully readable, using unsafe package, so that rules such as
+// time ones and custom validators can be applied to them, and their values are returned in FieldError.  It bypasses
+// Go's visibility rules and therefore should be used with care, eg. in internal tests only.  Values read this way are
+// used for validation only and are never modified.
+// * EmailRegexp overwrites regular expression used to validate emails, both the built-in one and the one set with
+// SetEmailRegexp
+// * FailFast makes only one failure flag returned for each field, instead of all the failed rules.  Rules that refer
+// to other fields and custom validators are not run for a field which value already failed, which is faster for large
+// structs with such rules, at the cost of less detailed result
+// * Conditions contains predicates for fields, and a field is validated only when its predicate returns true.
+// Predicate gets the object passed to Validate, so it can check other fields.  Similarly to SkipFields, they apply
+// to fields of the validated struct and not to the nested ones.
+// * EmptyValues contains values, keyed by kind, that are treated as zero value, eg. "N/A" string, so "req" fails for
+// them and "omitempty" skips them.  EmptyFieldValues does the same for fields by their name, eg. -1 for "Score" field,
+// and similarly to SkipFields, it applies to fields of the validated struct and not to the nested ones.
+// * PrefixWithType makes keys in the returned map prefixed with name of the struct type and a dot, eg. "User.Name",
+// so that results of validating different structs can be merged.  It applies to StructKey as well
+// * RequireAtLeastOneRule makes ValidateE return an error, and Validate panic, when none of the fields has any rule,
+// eg. because there are no tags or fields are of unsupported types, which likely means that a wrong object is
+// validated.  Fields are counted by their rules, so the ones skipped by omitempty, nil or RestrictFields count too
+// * AllowedValues contains allowed values, keyed by field name, eg. loaded from a database, and a field fails with
+// FailOneOf when its value is not one of them, the same as with "oneof" rule.  Similarly to SkipFields, it applies to
+// fields of the validated struct and not to the nested ones
+// * CombinedLenMax contains struct-level rules, keyed by a name, for maximal total length of string fields.  When it
+// fails, FailLenMax is returned with the name as a key, so it should not be the same as any field name
+// * ValidateEmbeddedInterfaces makes struct held by an embedded interface, or a pointer to it, validated recursively,
+// eg. a plugin implementation.  Its fields are not promoted so their keys are prefixed with the interface name, eg.
+// "Plugin.Name".  nil interface fails only when it is required
 type ValidationOptions struct {
-	RestrictFields       map[string]bool
-	OverwriteFieldTags   map[string]map[string]string
-	OverwriteTagName     string
-	ValidateWhenSuffix   bool
-	OverwriteFieldValues map[string]interface{}
+	RestrictFields             map[string]bool
+	RestrictFieldIndexes       map[int]bool
+	SkipFields                 map[string]bool
+	OverwriteFieldTags         map[string]map[string]string
+	OverwriteTagName           string
+	ValidateWhenSuffix         bool
+	OverwriteFieldValues       map[string]interface{}
+	UseTagNameInErrors         string
+	Validators                 map[string]ValidatorFunc
+	SetFieldsCount             []SetFieldsCount
+	FailureMessages            map[int64]string
+	ValidateUnexported         bool
+	EmailRegexp                *regexp.Regexp
+	FailFast                   bool
+	Conditions                 map[string]func(obj interface{}) bool
+	SuffixRules                map[string]string
+	DisableBuiltinSuffixRules  bool
+	StrictTags                 bool
+	OmitEmpty                  bool
+	EmptyValues                map[reflect.Kind][]interface{}
+	EmptyFieldValues           map[string][]interface{}
+	PrefixWithType             bool
+	FieldNameFunc              func(field reflect.StructField) string
+	RequireAtLeastOneRule      bool
+	AllowedValues              map[string][]string
+	CombinedLenMax             map[string]CombinedLen
+	ValidateEmbeddedInterfaces bool
+
+	// cache is set when validation is done with a Validator
+	cache *validationCache
+	// evaluatedFields is set by ValidateReport to collect keys of fields which rules were checked
+	evaluatedFields map[string]bool
+	// appliedRules counts fields which have any rule, when RequireAtLeastOneRule is set
+	appliedRules *int
+}
+
+// isEmptyValue checks if value of a field is one of the values that are treated as zero value
+func (o *ValidationOptions) isEmptyValue(fieldName string, value reflect.Value) bool {
+	if !value.IsValid() || (len(o.EmptyValues) == 0 && len(o.EmptyFieldValues) == 0) {
+		return false
+	}
+	for _, emptyValues := range [][]interface{}{o.EmptyValues[value.Kind()], o.EmptyFieldValues[fieldName]} {
+		for _, emptyValue := range emptyValues {
+			if isEqualValue(value, reflect.ValueOf(emptyValue)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isRestrictedOut checks if a field is not validated because of RestrictFields and RestrictFieldIndexes.  Field is
+// validated when it is listed by name or by index, or it is a prefix of a path, eg. "Address" in "Address.ZipCode".
+func (o *ValidationOptions) isRestrictedOut(fieldName string, index int) bool {
+	if len(o.RestrictFields) == 0 && len(o.RestrictFieldIndexes) == 0 {
+		return false
+	}
+	if o.RestrictFields[fieldName] || o.RestrictFieldIndexes[index] {
+		return false
+	}
+	return len(getNestedPaths(o.RestrictFields, fieldName)) == 0
+}
+
+// getNestedPaths returns paths from RestrictFields that are in fieldName field, without the field name and a dot,
+// eg. "ZipCode" for "Address.ZipCode"
+func getNestedPaths(restrictFields map[string]bool, fieldName string) map[string]bool {
+	var paths map[string]bool
+	for key := range restrictFields {
+		if !strings.HasPrefix(key, fieldName+".") {
+			continue
+		}
+		if paths == nil {
+			paths = map[string]bool{}
+		}
+		paths[strings.TrimPrefix(key, fieldName+".")] = true
+	}
+	return paths
+}
+
+// setEvaluated marks a field as one which rules were checked, when this is collected
+func (o *ValidationOptions) setEvaluated(fieldKey string) {
+	if o.evaluatedFields != nil {
+		o.evaluatedFields[fieldKey] = true
+	}
+}
+
+// countRules counts a field when it has any rule, when this is collected
+func (o *ValidationOptions) countRules(validation *ValueValidation) {
+	if o.appliedRules != nil && !validation.isEmpty() {
+		*o.appliedRules = *o.appliedRules + 1
+	}
+}
+
+// Validate validates fields of a struct.  Currently only fields which are string, bool, int (any), float (any), slice,
+// array, map or time.Time are validated.  For slices, arrays and maps, lenmin, lenmax and len rules apply to the number
+// of elements.  Kind of a field is checked, so named types such as `type Status string` are validated as well.
+// Fields that are channels or funcs fail with FailNil when they are required and nil.
+// Fields that are interfaces are validated by the value they hold, and nil ones fail only when they are required.
+// Nullable wrappers, such as sql.NullString, are validated by the value they hold, and null ones fail with FailNil
+// only when they are required.
+// Fields that are structs, or pointers to structs, are validated recursively with keys in the returned map prefixed
+// with the field name and a dot, eg. "Profile.FirstName", except time.Time and nullable wrappers.  Similarly, structs in
+// slices and arrays are validated with keys prefixed with the field name and an index, eg. "Items[0].Quantity".
+// Fields of embedded structs are promoted and validated as if they were declared on the outer struct.  When a field
+// of the outer struct has the same name, the embedded one is not validated.
+// Func returns boolean value that determines whether value is true or false, and a map of fields that failed
+// validation.  See Fail* constants for the values, and ValidateFlags64 on 32-bit platforms.
+// Func panics when validation tags are invalid, eg. regular expression cannot be compiled - use ValidateE to get an
+// error instead.
+// It is safe to call Validate, and other funcs of the package, from many goroutines at the same time.
+func Validate(obj interface{}, options *ValidationOptions) (bool, map[string]int) {
+	valid, invalidFields, err := ValidateE(obj, options)
+	if err != nil {
+		panic(err.Error())
+	}
+	return valid, invalidFields
+}
+
+// ValidateE works the same as Validate but returns an error instead of panicking when validation tags are invalid.
+func ValidateE(obj interface{}, options *ValidationOptions) (bool, map[string]int, error) {
+	valid, fieldErrors, err := validate(obj, options)
+	if err != nil {
+		return false, nil, err
+	}
+
+	invalidFields := make(map[string]int, len(fieldErrors))
+	for _, fieldError := range fieldErrors {
+		invalidFields[fieldError.Field] = int(fieldError.Flags)
+	}
+	return valid, invalidFields, nil
+}
+
+// ValidateFlags64 works the same as Validate but returns failure flags as int64.  Flags from FailGtField onwards do not
+// fit in int on 32-bit platforms, so this func should be used there when any of these rules can fail.
+func ValidateFlags64(obj interface{}, options *ValidationOptions) (bool, map[string]int64) {
+	valid, fieldErrors := ValidateDetailed(obj, options)
+
+	invalidFields := make(map[string]int64, len(fieldErrors))
+	for _, fieldError := range fieldErrors {
+		invalidFields[fieldError.Field] = fieldError.Flags
+	}
+	return valid, invalidFields
+}
+
+// ValidateWithSchema works the same as Validate but rules of the fields are taken from schema, which maps field name
+// to validation tag value, eg. "Email": "req email".  Schema overrides tags of the struct, as well as
+// OverwriteFieldTags, for the listed fields, and other fields are validated with their tags.  Similarly to
+// OverwriteFieldTags, schema applies to fields of the validated struct and not to the nested ones.
+func ValidateWithSchema(obj interface{}, schema map[string]string, options *ValidationOptions) (bool, map[string]int) {
+	if options == nil {
+		options = &ValidationOptions{}
+	}
+
+	tagName := "validation"
+	if options.OverwriteTagName != "" {
+		tagName = options.OverwriteTagName
+	}
+
+	overwriteFieldTags := make(map[string]map[string]string, len(options.OverwriteFieldTags)+len(schema))
+	for fieldName, tags := range options.OverwriteFieldTags {
+		overwriteFieldTags[fieldName] = tags
+	}
+	for fieldName, tag := range schema {
+		tags := make(map[string]string, len(overwriteFieldTags[fieldName])+1)
+		for name, val := range overwriteFieldTags[fieldName] {
+			tags[name] = val
+		}
+		tags[tagName] = tag
+		overwriteFieldTags[fieldName] = tags
+	}
+
+	optionsWithSchema := *options
+	optionsWithSchema.OverwriteFieldTags = overwriteFieldTags
+	return Validate(obj, &optionsWithSchema)
+}
+
+// ValidateReport works the same as Validate but additionally returns keys of the fields which rules were checked, so
+// that a valid field can be told apart from one that was not validated, eg. because of its type, RestrictFields or
+// omitempty.  Fields of nested structs are returned with their keys, eg. "Profile.FirstName", and the field with
+// nested struct itself is not.  Similarly to Validate, func panics when validation tags are invalid.
+func ValidateReport(obj interface{}, options *ValidationOptions) (bool, map[string]int, map[string]bool) {
+	optionsWithReport := ValidationOptions{}
+	if options != nil {
+		optionsWithReport = *options
+	}
+	optionsWithReport.evaluatedFields = map[string]bool{}
+
+	valid, invalidFields := Validate(obj, &optionsWithReport)
+	return valid, invalidFields, optionsWithReport.evaluatedFields
+}
+
+// ValidateDiff works the same as Validate but only fields which values in updated differ from the ones in original
+// are validated, so that unchanged legacy data does not fail an update.  Fields are compared with reflect.DeepEqual,
+// fields of embedded structs are compared one by one, and a nested struct is validated as a whole when anything in it
+// changed.  Unexported fields cannot be compared so they are always validated.  original and updated must be of the
+// same struct type, or pointers to it, and nil original means that all the fields changed.  A field with a rule that
+// refers to a changed field, eg. gtfield or required_with, is validated as well.  When RestrictFields is set, only the
+// changed fields listed in it are validated, while RestrictFieldIndexes is not used.  Similarly to Validate, func
+// panics when validation tags are invalid.
+func ValidateDiff(original interface{}, updated interface{}, options *ValidationOptions) (bool, map[string]int) {
+	originalValue := reflect.ValueOf(original)
+	if !originalValue.IsValid() || (originalValue.Kind() == reflect.Ptr && originalValue.IsNil()) {
+		return Validate(updated, options)
+	}
+	originalValue = reflect.Indirect(originalValue)
+	updatedValue := reflect.Indirect(reflect.ValueOf(updated))
+	if originalValue.Type() != updatedValue.Type() || updatedValue.Kind() != reflect.Struct {
+		panic("ValidateDiff requires original and updated of the same struct type")
+	}
+
+	optionsWithDiff := ValidationOptions{}
+	if options != nil {
+		optionsWithDiff = *options
+	}
+	changedFields := map[string]bool{}
+	setChangedFields(originalValue, updatedValue, &optionsWithDiff, false, changedFields)
+
+	tagName := "validation"
+	if optionsWithDiff.OverwriteTagName != "" {
+		tagName = optionsWithDiff.OverwriteTagName
+	}
+	for _, name := range getDependentFields(updatedValue.Type(), &optionsWithDiff, tagName, changedFields) {
+		changedFields[name] = true
+	}
+	if len(changedFields) == 0 {
+		return true, map[string]int{}
+	}
+	optionsWithDiff.RestrictFields = changedFields
+	optionsWithDiff.RestrictFieldIndexes = nil
+
+	return Validate(updated, &optionsWithDiff)
+}
+
+// getDependentFields returns names of fields in struct type s, and its embedded structs, which rules refer to any of
+// the changed fields, eg. "gtfield:StartTs" when StartTs changed, as their result can change as well.  Names are the
+// ones used in RestrictFields.
+func getDependentFields(s reflect.Type, options *ValidationOptions, tagName string, changedFields map[string]bool) []string {
+	dependent := []string{}
+	for j := 0; j < s.NumField(); j++ {
+		field := s.Field(j)
+
+		if field.Anonymous && isEmbeddedStruct(field.Type) {
+			t := field.Type
+			if t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			// struct embedding a pointer to its own type is not recursed
+			if t != s {
+				dependent = append(dependent, getDependentFields(t, options, tagName, changedFields)...)
+			}
+			continue
+		}
+
+		name := options.getRestrictName(&field)
+		if changedFields[name] || (len(options.RestrictFields) > 0 && !options.RestrictFields[name]) {
+			continue
+		}
+		// invalid tag is reported by Validate when the field is validated
+		validation, err := getFieldValidation(s, j, tagName, options)
+		if err != nil {
+			continue
+		}
+
+		references := append([]string{validation.EqField, validation.GtField, validation.GteField, validation.LtField, validation.LteField, validation.WithinField}, validation.RequiredWith...)
+		references = append(references, validation.RequiredWithout...)
+		for _, reference := range references {
+			otherField, exists := s.FieldByName(reference)
+			if reference != "" && exists && changedFields[options.getRestrictName(&otherField)] {
+				dependent = append(dependent, name)
+				break
+			}
+		}
+	}
+	return dependent
+}
+
+// setChangedFields adds names of fields that differ between two values of the same struct type to changedFields.
+// Fields of embedded structs are compared one by one, and when embedded pointer is nil in only one of the values, all
+// of its fields are changed.  When all is true, all the fields are added without comparing them.  When RestrictFields
+// in options is not empty, only fields listed in it are added.  Names are the ones matched with RestrictFields.
+func setChangedFields(a reflect.Value, b reflect.Value, options *ValidationOptions, all bool, changedFields map[string]bool) {
+	restrictFields := options.RestrictFields
+	s := a.Type()
+	for j := 0; j < s.NumField(); j++ {
+		field := s.Field(j)
+		name := options.getRestrictName(&field)
+		aField := a.Field(j)
+		bField := b.Field(j)
+
+		if field.Anonymous && isEmbeddedStruct(field.Type) {
+			if field.Type.Kind() == reflect.Ptr {
+				if aField.IsNil() && bField.IsNil() && !all {
+					continue
+				}
+				// nil embedded pointer is checked as a field itself, when it is required, and all its fields are changed.
+				// Struct embedding a pointer to its own type is not recursed.
+				if aField.IsNil() || bField.IsNil() {
+					changedFields[name] = true
+					if field.Type.Elem() != s {
+						zero := reflect.New(field.Type.Elem()).Elem()
+						setChangedFields(zero, zero, options, true, changedFields)
+					}
+					continue
+				}
+				aField = aField.Elem()
+				bField = bField.Elem()
+			}
+			setChangedFields(aField, bField, options, all, changedFields)
+			continue
+		}
+
+		// values of unexported fields cannot be compared so they are always validated
+		if !all && aField.CanInterface() && reflect.DeepEqual(aField.Interface(), bField.Interface()) {
+			continue
+		}
+		// paths to nested fields in RestrictFields, eg. "Address.ZipCode", are kept
+		if len(restrictFields) == 0 || restrictFields[name] {
+			changedFields[name] = true
+			continue
+		}
+		for path := range getNestedPaths(restrictFields, name) {
+			changedFields[name+"."+path] = true
+		}
+	}
+}
+
+// ValidateMany validates each struct in a slice (or array) of structs or pointers to structs.  It returns false when
+// any of them is invalid, and a map of failed fields (as in Validate) keyed by index of the element.  Nil elements
+// are skipped.  Similarly to Validate, func panics when validation tags are invalid.
+func ValidateMany(slice interface{}, options *ValidationOptions) (bool, map[int]map[string]int) {
+	v := reflect.ValueOf(slice)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		panic("ValidateMany requires a slice or an array")
+	}
+
+	valid := true
+	invalidElements := map[int]map[string]int{}
+	for j := 0; j < v.Len(); j++ {
+		elem := v.Index(j)
+		if elem.Kind() == reflect.Ptr && elem.IsNil() {
+			continue
+		}
+
+		elemValid, invalidFields := Validate(elem.Interface(), options)
+		if !elemValid {
+			valid = false
+			invalidElements[j] = invalidFields
+		}
+	}
+	return valid, invalidElements
+}
+
+// ValidateField validates a single value against validation tag, and tag with a regular expression (the same as the
+// value of "validation_regexp" tag), without a struct.  Rules that refer to other fields, such as eqfield, are
+// ignored.  Func returns boolean value that determines whether value is valid, and failure flags.  Similarly to
+// Validate, func panics when tags are invalid.
+func ValidateField(value interface{}, tag string, tagRegexp string) (bool, int64) {
+	validation := NewValueValidation()
+	err := setValidationFromTags(validation, tag, tagRegexp, "", defaultCache)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	v := reflect.ValueOf(value)
+	if !v.IsValid() {
+		if validation.Flags&Required > 0 {
+			return false, FailNil
+		}
+		return true, 0
+	}
+
+	f := validation.validate(v)
+	if len(validation.Custom) > 0 && !f.isMissing() {
+		customFailures, err := runCustomValidators(validation.Custom, v, nil)
+		if err != nil {
+			panic(err.Error())
+		}
+		f.append(customFailures)
+	}
+	return len(f) == 0, f.flags()
+}
+
+// ValidateMap validates a map, eg. JSON decoded into map[string]interface{}, without a struct.  Rules are taken from
+// schema, which maps a key to validation tag value, eg. "email": "req email", and only the listed keys are validated.
+// Value is validated by its dynamic kind, so a JSON number is a float64, and a whole number is validated as an int64
+// when rules apply to ints only, eg. ranges.  Missing key is an empty value and it fails with FailEmpty when it is
+// required, while nil value fails with FailNil.  Value of a kind that the rules do not apply to, eg. a number with
+// email rule, or a number that is not whole with rules for ints only, fails with FailType.  Rules that refer to other
+// fields, such as eqfield, are ignored.  StrictTags, EmptyValues, OmitEmpty, EmailRegexp, Validators and FailFast
+// options are used, and keys are used as field names in EmptyFieldValues, while other options are ignored.  Similarly
+// to Validate, func panics when tags are invalid.
+func ValidateMap(m map[string]interface{}, schema map[string]string, options *ValidationOptions) (bool, map[string]int64) {
+	if options == nil {
+		options = &ValidationOptions{}
+	}
+
+	keys := make([]string, 0, len(schema))
+	for key := range schema {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	valid := true
+	invalidFields := map[string]int64{}
+	for _, key := range keys {
+		if options.StrictTags {
+			err := checkTagTokens(schema[key])
+			if err != nil {
+				panic(fmt.Sprintf("invalid validation tag in key %s: %s", key, err.Error()))
+			}
+		}
+		validation := NewValueValidation()
+		err := setValidationFromTags(validation, schema[key], "", "", getCache(options))
+		if err != nil {
+			panic(fmt.Sprintf("invalid validation tag in key %s: %s", key, err.Error()))
+		}
+		if options.EmailRegexp != nil {
+			validation.EmailRegexp = options.EmailRegexp
+		}
+
+		val, exists := m[key]
+		if !exists || val == nil {
+			if validation.Flags&Required > 0 {
+				valid = false
+				invalidFields[key] = FailNil
+				if !exists {
+					invalidFields[key] = FailEmpty
+				}
+			}
+			continue
+		}
+
+		v := reflect.ValueOf(val)
+		if options.isEmptyValue(key, v) {
+			v = reflect.Zero(v.Type())
+		}
+		if validation.Flags&Required == 0 && (options.OmitEmpty || validation.Flags&OmitEmpty > 0) && v.IsZero() {
+			continue
+		}
+		if !validation.appliesTo(v.Kind()) {
+			valid = false
+			invalidFields[key] = FailType
+			continue
+		}
+		if isFloat(v.Kind()) && validation.hasIntRules() {
+			if v.Float() != math.Trunc(v.Float()) || math.Abs(v.Float()) >= math.MaxInt64 {
+				valid = false
+				invalidFields[key] = FailType
+				continue
+			}
+			v = reflect.ValueOf(int64(v.Float()))
+		}
+
+		f := validation.validate(v)
+		if len(validation.Custom) > 0 && !f.isMissing() {
+			customFailures, err := runCustomValidators(validation.Custom, v, options.Validators)
+			if err != nil {
+				panic(fmt.Sprintf("invalid custom validator in key %s: %s", key, err.Error()))
+			}
+			f.append(customFailures)
+		}
+		if len(f) > 0 {
+			if options.FailFast {
+				f = f.first()
+			}
+			valid = false
+			invalidFields[key] = f.flags()
+		}
+	}
+	return valid, invalidFields
+}
+
+// RulesFor returns rules parsed from tags of struct fields, keyed the same way as in the map returned by Validate,
+// without validating any value, eg. to generate documentation.  obj can be a struct, or a pointer to it which can be
+// nil.  Fields of embedded structs are included, while fields of nested structs are not.  OverwriteFieldTags,
+// OverwriteTagName, UseTagNameInErrors, FieldNameFunc, ValidateWhenSuffix and SuffixRules are respected.  Returned
+// values are copies but their slices and regular expressions are shared and must not be modified.  Similarly to
+// Validate, func panics when validation tags are invalid.
+func RulesFor(obj interface{}, options *ValidationOptions) map[string]*ValueValidation {
+	if options == nil {
+		options = &ValidationOptions{}
+	}
+
+	s := reflect.TypeOf(obj)
+	for s.Kind() == reflect.Ptr {
+		s = s.Elem()
+	}
+	if s.Kind() != reflect.Struct {
+		panic("RulesFor requires a struct or a pointer to struct")
+	}
+
+	tagName := "validation"
+	if options.OverwriteTagName != "" {
+		tagName = options.OverwriteTagName
+	}
+
+	rules := map[string]*ValueValidation{}
+	err := setStructRules(s, options, tagName, nil, map[reflect.Type]bool{}, rules)
+	if err != nil {
+		panic(err.Error())
+	}
+	return rules
+}
+
+// setStructRules adds rules of fields of struct s, and structs embedded in it, to rules map.  visited contains types
+// of the embedded structs so that embedding a pointer to the same type does not recurse infinitely.
+func setStructRules(s reflect.Type, options *ValidationOptions, tagName string, shadowed map[string]bool, visited map[reflect.Type]bool, rules map[string]*ValueValidation) error {
+	visited[s] = true
+	var embeddedShadowed map[string]bool
+	for j := 0; j < s.NumField(); j++ {
+		field := s.Field(j)
+		if shadowed[field.Name] {
+			continue
+		}
+
+		if field.Anonymous && isEmbeddedStruct(field.Type) {
+			embeddedType := field.Type
+			if embeddedType.Kind() == reflect.Ptr {
+				embeddedType = embeddedType.Elem()
+			}
+			if visited[embeddedType] {
+				continue
+			}
+			if embeddedShadowed == nil {
+				embeddedShadowed = getShadowedFields(s, shadowed)
+			}
+			err := setStructRules(embeddedType, options, tagName, embeddedShadowed, visited, rules)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !isValidatedType(field.Type) {
+			continue
+		}
+
+		fieldKey := options.getFieldKey(&field)
+		validation, err := getFieldValidation(s, j, tagName, options)
+		if err != nil {
+			return fmt.Errorf("invalid validation tag in field %s: %w", fieldKey, err)
+		}
+		validationCopy := *validation
+		rules[fieldKey] = &validationCopy
+	}
+	return nil
+}
+
+// validate is an implementation of Validate.  It returns FieldError for each field that failed, in order of struct
+// fields.
+func validate(obj interface{}, options *ValidationOptions) (bool, []FieldError, error) {
+	// nil ValidationOptions is the same as empty ones
+	if options == nil {
+		options = &ValidationOptions{}
+	}
+
+	v := reflect.ValueOf(obj)
+	i := reflect.Indirect(v)
+	s := i.Type()
+
+	// TODO: Fix this to traverse the pointer behind reflect.Value properly.  Current this is made to support
+	// struct-db-postgres module that uses this validator.
+	if s.String() == "reflect.Value" {
+		s = reflect.ValueOf(obj.(reflect.Value).Interface()).Type().Elem().Elem()
+		i = reflect.New(s).Elem()
+	}
+
+	tagName := "validation"
+	if options.OverwriteTagName != "" {
+		tagName = options.OverwriteTagName
+	}
+
+	options = getOptionsWithConditions(obj, options)
+
+	// fields with rules are counted on a copy of options, as the passed ones can be used concurrently
+	if options.RequireAtLeastOneRule {
+		optionsWithCount := *options
+		optionsWithCount.appliedRules = new(int)
+		options = &optionsWithCount
+	}
+
+	keyPrefix := ""
+	if options.PrefixWithType && s.Name() != "" {
+		keyPrefix = s.Name() + "."
+	}
+
+	fieldErrors := []FieldError{}
+	valid, err := validateStruct(i, options, tagName, keyPrefix, nil, nil, &fieldErrors)
+	if err != nil {
+		return false, nil, err
+	}
+
+	// struct-level rules are checked after all the fields
+	structFailures, err := validateSetFieldsCount(i, options)
+	if err != nil {
+		return false, nil, err
+	}
+	if options.RequireAtLeastOneRule && *options.appliedRules == 0 && len(options.SetFieldsCount) == 0 && len(options.CombinedLenMax) == 0 {
+		return false, nil, fmt.Errorf("no validation rule was checked for struct %s", s.String())
+	}
+	combinedLenValid, err := validateCombinedLenMax(i, options, keyPrefix, &fieldErrors)
+	if err != nil {
+		return false, nil, err
+	}
+	if !combinedLenValid {
+		valid = false
+	}
+	if len(structFailures) > 0 {
+		valid = false
+		fieldErrors = append(fieldErrors, newFieldError(keyPrefix+StructKey, reflect.Value{}, structFailures, nil))
+	}
+
+	return valid, fieldErrors, nil
+}
+
+// getOptionsWithConditions returns a copy of options where fields which Conditions returned false are added to
+// SkipFields.  When there are no conditions, options are returned as they are.
+func getOptionsWithConditions(obj interface{}, options *ValidationOptions) *ValidationOptions {
+	if len(options.Conditions) == 0 {
+		return options
+	}
+
+	skipFields := make(map[string]bool, len(options.SkipFields)+len(options.Conditions))
+	for name, skip := range options.SkipFields {
+		skipFields[name] = skip
+	}
+	for name, condition := range options.Conditions {
+		if !condition(obj) {
+			skipFields[name] = true
+		}
+	}
+
+	optionsWithConditions := *options
+	optionsWithConditions.SkipFields = skipFields
+	return &optionsWithConditions
+}
+
+// validateSetFieldsCount checks SetFieldsCount rules from ValidationOptions and returns FailFieldsMin and
+// FailFieldsMax failures for the ones that failed
+func validateSetFieldsCount(structValue reflect.Value, options *ValidationOptions) (failures, error) {
+	var f failures
+	for _, rule := range options.SetFieldsCount {
+		count := 0
+		for _, fieldName := range rule.Fields {
+			field, exists := structValue.Type().FieldByName(fieldName)
+			if !exists {
+				return nil, fmt.Errorf("field %s referenced in SetFieldsCount does not exist", fieldName)
+			}
+			fieldValue, err := getFieldValue(structValue, &field, options)
+			if err != nil {
+				return nil, err
+			}
+			if fieldValue.IsValid() && !fieldValue.IsZero() {
+				count++
+			}
+		}
+
+		if count < rule.Min {
+			f.add(FailFieldsMin, "setfieldscount")
+		}
+		if rule.Max > 0 && count > rule.Max {
+			f.add(FailFieldsMax, "setfieldscount")
+		}
+	}
+	return f, nil
+}
+
+// validateCombinedLenMax checks CombinedLenMax rules from ValidationOptions and appends FieldError, with FailLenMax
+// and the rule name as a key, and the total length as a value, for the ones that failed.  Rules are checked in order
+// of their names.  Length of a string is a number of bytes, and nil pointer to a string has zero length.  An error is
+// returned when a referenced field does not exist or it is not a string.
+func validateCombinedLenMax(structValue reflect.Value, options *ValidationOptions, keyPrefix string, fieldErrors *[]FieldError) (bool, error) {
+	names := make([]string, 0, len(options.CombinedLenMax))
+	for name := range options.CombinedLenMax {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	valid := true
+	for _, name := range names {
+		rule := options.CombinedLenMax[name]
+		length := 0
+		for _, fieldName := range rule.Fields {
+			field, exists := structValue.Type().FieldByName(fieldName)
+			if !exists {
+				return false, fmt.Errorf("field %s referenced in CombinedLenMax %s does not exist", fieldName, name)
+			}
+			fieldValue, err := getFieldValue(structValue, &field, options)
+			if err != nil {
+				return false, err
+			}
+			if fieldValue.Kind() == reflect.Ptr && !fieldValue.IsNil() {
+				fieldValue = fieldValue.Elem()
+			}
+			if fieldValue.Kind() == reflect.Ptr && field.Type.Elem().Kind() == reflect.String {
+				continue
+			}
+			if fieldValue.Kind() != reflect.String {
+				return false, fmt.Errorf("field %s referenced in CombinedLenMax %s is not a string", fieldName, name)
+			}
+			length = length + len(fieldValue.String())
+		}
+
+		if length > rule.Max {
+			valid = false
+			// validation with the maximum is used for the message
+			validation := NewValueValidation()
+			validation.LenMax = rule.Max
+			*fieldErrors = append(*fieldErrors, newFieldError(keyPrefix+name, reflect.ValueOf(length), failures{{flag: FailLenMax, name: "lenmax"}}, validation))
+		}
+	}
+	return valid, nil
+}
+
+// structAddr is an address of a struct with its type, as an embedded struct can have the same address as the outer one
+type structAddr struct {
+	ptr uintptr
+	t   reflect.Type
+}
+
+// validateStruct validates fields of a struct value and appends FieldError for the failed ones to fieldErrors, with
+// their names prefixed with keyPrefix.  It is called recursively for fields that are structs or pointers to them, and
+// for embedded structs which fields are promoted.  Fields in shadowed are not validated, as they are shadowed by the
+// fields of the outer struct.  Structs in visited are the ones that are being validated higher in the recursion.
+func validateStruct(structValue reflect.Value, options *ValidationOptions, tagName string, keyPrefix string, shadowed map[string]bool, visited map[structAddr]bool, fieldErrors *[]FieldError) (bool, error) {
+	s := structValue.Type()
+	valid := true
+
+	// struct reached again through a cycle of pointers, eg. Prev and Next of a doubly-linked list, is not validated
+	// again as its fields are already being validated.  Only the current path is tracked, so the same struct pointed to
+	// by two fields is validated for both of them.
+	if structValue.CanAddr() {
+		addr := structAddr{ptr: structValue.UnsafeAddr(), t: s}
+		if visited[addr] {
+			return true, nil
+		}
+		if visited == nil {
+			visited = map[structAddr]bool{}
+		}
+		visited[addr] = true
+		defer delete(visited, addr)
+	}
+
+	// reading unexported fields requires their address so struct passed by value is copied
+	if options.ValidateUnexported && !structValue.CanAddr() {
+		addressable := reflect.New(s).Elem()
+		addressable.Set(structValue)
+		structValue = addressable
+	}
+
+	// fields of embedded structs are validated as if they were declared on this struct, unless there is a field with
+	// the same name here
+	var embeddedShadowed map[string]bool
+
+	for j := 0; j < s.NumField(); j++ {
+		field := s.Field(j)
+		fieldKind := field.Type.Kind()
+
+		if shadowed[field.Name] {
+			continue
+		}
+
+		if field.Anonymous && isEmbeddedStruct(field.Type) {
+			if embeddedShadowed == nil {
+				embeddedShadowed = getShadowedFields(s, shadowed)
+			}
+
+			// nil pointer to embedded struct has no fields to validate, and it fails only when it is required
+			embeddedValue := structValue.Field(j)
+			if embeddedValue.Kind() == reflect.Ptr {
+				if embeddedValue.IsNil() {
+					embeddedValid, err := validateNilEmbedded(s, j, options, tagName, keyPrefix, fieldErrors)
+					if err != nil {
+						return false, err
+					}
+					if !embeddedValid {
+						valid = false
+					}
+					continue
+				}
+				embeddedValue = embeddedValue.Elem()
+			}
+
+			embeddedValid, err := validateStruct(embeddedValue, options, tagName, keyPrefix, embeddedShadowed, visited, fieldErrors)
+			if err != nil {
+				return false, err
+			}
+			if !embeddedValid {
+				valid = false
+			}
+			continue
+		}
+
+		// rules are counted before the field can be skipped, as RequireAtLeastOneRule is about rules of the struct and
+		// not its values, eg. an empty field with "omitempty lenmin:3" counts
+		if options.appliedRules != nil && isValidatedType(field.Type) {
+			validation, err := getFieldValidation(s, j, tagName, options)
+			if err != nil {
+				return false, fmt.Errorf("invalid validation tag in field %s: %w", keyPrefix+options.getFieldKey(&field), err)
+			}
+			options.countRules(validation)
+		}
+
+		// check if only specified field should be checked, by name or by index
+		if options.isRestrictedOut(options.getRestrictName(&field), j) {
+			continue
+		}
+
+		// check if field should be skipped
+		if options.SkipFields[field.Name] {
+			continue
+		}
+
+		// struct is validated recursively, the same as the one behind a pointer, while time.Time and nullable wrappers
+		// are validated as values below
+		if fieldKind == reflect.Struct && !isTime(field.Type) && !isNullable(field.Type) {
+			fieldValue, err := getFieldValue(structValue, &field, options)
+			if err != nil {
+				return false, err
+			}
+			nestedValid, err := validateStruct(fieldValue, getNestedOptions(options, &field), tagName, keyPrefix+options.getFieldKey(&field)+".", nil, visited, fieldErrors)
+			if err != nil {
+				return false, err
+			}
+			if !nestedValid {
+				valid = false
+			}
+			continue
+		}
+
+		if !isValidatedType(field.Type) {
+			continue
+		}
+
+		fieldKey := keyPrefix + options.getFieldKey(&field)
+
+		// struct held by an embedded interface is validated as a nested struct, and nil interface, or nil pointer in
+		// it, is validated below as any other interface field
+		if options.ValidateEmbeddedInterfaces && field.Anonymous && fieldKind == reflect.Interface && !structValue.Field(j).IsNil() {
+			embeddedValue := structValue.Field(j).Elem()
+			if embeddedValue.Kind() == reflect.Ptr && !embeddedValue.IsNil() {
+				embeddedValue = embeddedValue.Elem()
+			}
+			if embeddedValue.Kind() == reflect.Struct && !isTime(embeddedValue.Type()) {
+				nestedValid, err := validateStruct(embeddedValue, getNestedOptions(options, &field), tagName, fieldKey+".", nil, visited, fieldErrors)
+				if err != nil {
+					return false, err
+				}
+				if !nestedValid {
+					valid = false
+				}
+				continue
+			}
+		}
+
+		validation, err := getFieldValidation(s, j, tagName, options)
+		if err != nil {
+			return false, fmt.Errorf("invalid validation tag in field %s: %w", fieldKey, err)
+		}
+
+		fieldValue, err := getFieldValue(structValue, &field, options)
+		if err != nil {
+			return false, err
+		}
+
+		// nullable wrapper, eg. sql.NullString, is validated by the value it holds, and when it is null then it fails
+		// only when field is required
+		isNull := false
+		if fieldValue.IsValid() && isNullable(fieldValue.Type()) {
+			fieldValue, isNull = getNullableValue(fieldValue)
+		}
+
+		// values from EmptyValues and EmptyFieldValues are validated as if they were zero value
+		if options.isEmptyValue(field.Name, fieldValue) {
+			fieldValue = reflect.Zero(fieldValue.Type())
+		}
+
+		// field becomes required when any of the fields in required_with is not zero.  Cached validation cannot be
+		// modified so it is copied.
+		if len(validation.RequiredWith) > 0 && validation.Flags&Required == 0 {
+			for _, otherFieldName := range validation.RequiredWith {
+				otherField, exists := s.FieldByName(otherFieldName)
+				if !exists {
+					return false, fmt.Errorf("field %s referenced in required_with of field %s does not exist", otherFieldName, fieldKey)
+				}
+				otherValue, err := getFieldValue(structValue, &otherField, options)
+				if err != nil {
+					return false, err
+				}
+				if otherValue.IsValid() && !otherValue.IsZero() {
+					conditionalValidation := *validation
+					conditionalValidation.Flags = conditionalValidation.Flags | Required
+					validation = &conditionalValidation
+					break
+				}
+			}
+		}
+
+		// field becomes required when all of the fields in required_without are zero
+		if len(validation.RequiredWithout) > 0 && validation.Flags&Required == 0 {
+			allAbsent := true
+			for _, otherFieldName := range validation.RequiredWithout {
+				otherField, exists := s.FieldByName(otherFieldName)
+				if !exists {
+					return false, fmt.Errorf("field %s referenced in required_without of field %s does not exist", otherFieldName, fieldKey)
+				}
+				otherValue, err := getFieldValue(structValue, &otherField, options)
+				if err != nil {
+					return false, err
+				}
+				if otherValue.IsValid() && !otherValue.IsZero() {
+					allAbsent = false
+					break
+				}
+			}
+			if allAbsent {
+				conditionalValidation := *validation
+				conditionalValidation.Flags = conditionalValidation.Flags | Required
+				validation = &conditionalValidation
+			}
+		}
+
+		// email regular expression from options is set on a copy of cached validation
+		if options.EmailRegexp != nil && validation.Flags&Email > 0 {
+			emailValidation := *validation
+			emailValidation.EmailRegexp = options.EmailRegexp
+			validation = &emailValidation
+		}
+
+		if isNull {
+			if validation.Flags&Required > 0 {
+				options.setEvaluated(fieldKey)
+				valid = false
+				*fieldErrors = append(*fieldErrors, newFieldError(fieldKey, fieldValue, failures{{flag: FailNil, name: validation.getRequiredRule()}}, validation))
+			}
+			continue
+		}
+
+		// with omitempty, zero value of an optional field is not validated at all
+		if validation.Flags&Required == 0 && (options.OmitEmpty || validation.Flags&OmitEmpty > 0) && (!fieldValue.IsValid() || fieldValue.IsZero()) {
+			continue
+		}
+
+		// interface is validated by its dynamic value, which is dereferenced when it is a pointer.  nil fails only when
+		// field is required.
+		if fieldKind == reflect.Interface {
+			if fieldValue.IsValid() && fieldValue.Kind() == reflect.Interface && !fieldValue.IsNil() {
+				fieldValue = fieldValue.Elem()
+			}
+			if fieldValue.IsValid() && fieldValue.Kind() == reflect.Ptr && !fieldValue.IsNil() {
+				fieldValue = fieldValue.Elem()
+			}
+			if !fieldValue.IsValid() || ((fieldValue.Kind() == reflect.Interface || fieldValue.Kind() == reflect.Ptr) && fieldValue.IsNil()) {
+				if validation.Flags&Required > 0 {
+					options.setEvaluated(fieldKey)
+					valid = false
+					*fieldErrors = append(*fieldErrors, newFieldError(fieldKey, fieldValue, failures{{flag: FailNil, name: validation.getRequiredRule()}}, validation))
+				}
+				continue
+			}
+			elemKind := fieldValue.Kind()
+			if !isInt(elemKind) && !isFloat(elemKind) && elemKind != reflect.String && elemKind != reflect.Bool && elemKind != reflect.Slice && elemKind != reflect.Array && elemKind != reflect.Map && !isTime(fieldValue.Type()) {
+				continue
+			}
+		}
+
+		// nil pointer fails only when field is required, non-nil pointer is dereferenced and struct behind it is
+		// validated recursively
+		if fieldKind == reflect.Ptr {
+			if !fieldValue.IsValid() || (fieldValue.Kind() == reflect.Ptr && fieldValue.IsNil()) {
+				if validation.Flags&Required > 0 {
+					options.setEvaluated(fieldKey)
+					valid = false
+					*fieldErrors = append(*fieldErrors, newFieldError(fieldKey, fieldValue, failures{{flag: FailNil, name: validation.getRequiredRule()}}, validation))
+				}
+				continue
+			}
+
+			fieldValue = reflect.Indirect(fieldValue)
+			elemKind := fieldValue.Kind()
+			if elemKind == reflect.Struct && !isTime(fieldValue.Type()) {
+				nestedValid, err := validateStruct(fieldValue, getNestedOptions(options, &field), tagName, fieldKey+".", nil, visited, fieldErrors)
+				if err != nil {
+					return false, err
+				}
+				if !nestedValid {
+					valid = false
+				}
+				continue
+			}
+			if !isInt(elemKind) && !isFloat(elemKind) && elemKind != reflect.String && elemKind != reflect.Bool && !isTime(fieldValue.Type()) {
+				continue
+			}
+		}
+
+		options.setEvaluated(fieldKey)
+		fieldFailures := validation.validate(fieldValue)
+		missing := fieldFailures.isMissing()
+
+		// with FailFast, rules that refer to other fields and custom validators are not run when value already failed
+		if len(fieldFailures) > 0 && options.FailFast {
+			valid = false
+			*fieldErrors = append(*fieldErrors, newFieldError(fieldKey, fieldValue, fieldFailures.first(), validation))
+			continue
+		}
+
+		// comparison with another field is done only when value is present
+		if validation.EqField != "" && !missing {
+			otherField, exists := s.FieldByName(validation.EqField)
+			if !exists {
+				return false, fmt.Errorf("field %s referenced in eqfield of field %s does not exist", validation.EqField, fieldKey)
+			}
+			otherValue, err := getFieldValue(structValue, &otherField, options)
+			if err != nil {
+				return false, err
+			}
+			if !isEqualValue(fieldValue, reflect.Indirect(otherValue)) {
+				fieldFailures.add(FailEqField, "eqfield")
+			}
+		}
+
+		// numeric comparisons with other fields are done only when value is present
+		if !missing {
+			comparisonFailures, err := compareWithFields(structValue, fieldValue, validation, options)
+			if err != nil {
+				return false, fmt.Errorf("invalid field comparison in field %s: %w", fieldKey, err)
+			}
+			fieldFailures.append(comparisonFailures)
+		}
+
+		// custom validators are run only when value is present
+		if len(validation.Custom) > 0 && !missing {
+			customFailures, err := runCustomValidators(validation.Custom, fieldValue, options.Validators)
+			if err != nil {
+				return false, fmt.Errorf("invalid custom validator in field %s: %w", fieldKey, err)
+			}
+			fieldFailures.append(customFailures)
+		}
+
+		// values allowed in options are checked the same way as oneof, when value is present
+		if allowed, exists := options.AllowedValues[field.Name]; exists && !missing {
+			allowedValidation := ValueValidation{OneOf: allowed}
+			if !allowedValidation.isOneOf(fieldValue) {
+				fieldFailures.add(FailOneOf, "oneof")
+				// allowed values are set on a copy of cached validation so that they are returned in the message
+				validationWithAllowed := *validation
+				validationWithAllowed.OneOf = allowed
+				validation = &validationWithAllowed
+			}
+		}
+
+		if len(fieldFailures) > 0 {
+			if options.FailFast {
+				fieldFailures = fieldFailures.first()
+			}
+			valid = false
+			*fieldErrors = append(*fieldErrors, newFieldError(fieldKey, fieldValue, fieldFailures, validation))
+		}
+
+		// elements of a slice or an array of structs, or pointers to structs, are validated recursively with keys like
+		// "Items[0].Quantity"
+		if (fieldValue.Kind() == reflect.Slice || fieldValue.Kind() == reflect.Array) && isEmbeddedStruct(fieldValue.Type().Elem()) {
+			elementsValid, err := validateStructElements(fieldKey, fieldValue, getNestedOptions(options, &field), tagName, visited, fieldErrors)
+			if err != nil {
+				return false, err
+			}
+			if !elementsValid {
+				valid = false
+			}
+		}
+
+		// elements of a slice or an array are validated with rules from "validation_elem" tag and failures are returned
+		// with keys like "Codes[2]"
+		if validation.Elements != nil && (fieldValue.Kind() == reflect.Slice || fieldValue.Kind() == reflect.Array) {
+			if !validateElements(fieldKey, fieldValue, validation.Elements, options, fieldErrors) {
+				valid = false
+			}
+		}
+
+		// keys and values of a map are validated separately and failures are returned with keys like "Meta[key]"
+		if (validation.Keys != nil || validation.Values != nil) && fieldValue.Kind() == reflect.Map {
+			if !validateMapEntries(fieldKey, fieldValue, validation, options, fieldErrors) {
+				valid = false
+			}
+		}
+	}
+
+	return valid, nil
+}
+
+// validateNilEmbedded checks nil pointer to an embedded struct, which is valid unless it is required.  It is
+// skipped the same way as other fields when RestrictFields, RestrictFieldIndexes or SkipFields are set.
+func validateNilEmbedded(s reflect.Type, index int, options *ValidationOptions, tagName string, keyPrefix string, fieldErrors *[]FieldError) (bool, error) {
+	field := s.Field(index)
+	fieldKey := keyPrefix + options.getFieldKey(&field)
+	if options.appliedRules != nil {
+		validation, err := getFieldValidation(s, index, tagName, options)
+		if err != nil {
+			return false, fmt.Errorf("invalid validation tag in field %s: %w", fieldKey, err)
+		}
+		options.countRules(validation)
+	}
+
+	if options.isRestrictedOut(options.getRestrictName(&field), index) {
+		return true, nil
+	}
+	if options.SkipFields[field.Name] {
+		return true, nil
+	}
+
+	validation, err := getFieldValidation(s, index, tagName, options)
+	if err != nil {
+		return false, fmt.Errorf("invalid validation tag in field %s: %w", fieldKey, err)
+	}
+	if validation.Flags&Required == 0 {
+		return true, nil
+	}
+
+	options.setEvaluated(fieldKey)
+	*fieldErrors = append(*fieldErrors, newFieldError(fieldKey, reflect.Zero(field.Type), failures{{flag: FailNil, name: validation.getRequiredRule()}}, validation))
+	return false, nil
+}
+
+// validateStructElements validates structs in a slice or an array.  nil pointers are skipped.
+func validateStructElements(fieldKey string, sliceValue reflect.Value, options *ValidationOptions, tagName string, visited map[structAddr]bool, fieldErrors *[]FieldError) (bool, error) {
+	valid := true
+	for j := 0; j < sliceValue.Len(); j++ {
+		elem := sliceValue.Index(j)
+		if elem.Kind() == reflect.Ptr {
+			if elem.IsNil() {
+				continue
+			}
+			elem = elem.Elem()
+		}
+
+		elemValid, err := validateStruct(elem, options, tagName, fmt.Sprintf("%s[%d].", fieldKey, j), nil, visited, fieldErrors)
+		if err != nil {
+			return false, err
+		}
+		if !elemValid {
+			valid = false
+		}
+	}
+	return valid, nil
+}
+
+// validateMapEntries validates keys and values of a map with Keys and Values validations, and appends FieldError for
+// each entry that failed to fieldErrors.  Failures of a key and its value are joined.  Entries are sorted by key so
+// that the order is deterministic.
+func validateMapEntries(fieldKey string, mapValue reflect.Value, validation *ValueValidation, options *ValidationOptions, fieldErrors *[]FieldError) bool {
+	keys := mapValue.MapKeys()
+	sort.Slice(keys, func(a, b int) bool {
+		return fmt.Sprint(keys[a]) < fmt.Sprint(keys[b])
+	})
+
+	valid := true
+	for _, key := range keys {
+		var entryFailures failures
+		if validation.Keys != nil {
+			for _, r := range validation.Keys.validate(key) {
+				r.name = keyRulePrefix + r.name
+				entryFailures.addRule(r)
+			}
+		}
+		keyFailed := len(entryFailures) > 0
+
+		value := mapValue.MapIndex(key)
+		if validation.Values != nil {
+			if value.Kind() == reflect.Interface || value.Kind() == reflect.Ptr {
+				if value.IsNil() {
+					if validation.Values.Flags&Required > 0 {
+						entryFailures.add(FailNil, validation.Values.getRequiredRule())
+					}
+					value = reflect.Value{}
+				} else {
+					value = value.Elem()
+				}
+			}
+			if value.IsValid() {
+				entryFailures.append(validation.Values.validate(value))
+			}
+		}
+
+		if len(entryFailures) > 0 {
+			if options.FailFast {
+				entryFailures = entryFailures.first()
+			}
+			// key is returned as the value when only the key failed
+			if keyFailed && len(entryFailures.withoutKeyRules()) == 0 {
+				value = key
+			}
+			valid = false
+			fieldError := newFieldError(fmt.Sprintf("%s[%v]", fieldKey, key), value, entryFailures, validation.Values)
+			fieldError.keyValidation = validation.Keys
+			*fieldErrors = append(*fieldErrors, fieldError)
+		}
+	}
+	return valid
+}
+
+// validateElements validates each element of a slice or an array.  Pointers and interfaces are dereferenced, and nil
+// ones fail only when elements are required.
+func validateElements(fieldKey string, sliceValue reflect.Value, validation *ValueValidation, options *ValidationOptions, fieldErrors *[]FieldError) bool {
+	valid := true
+	for j := 0; j < sliceValue.Len(); j++ {
+		elem := sliceValue.Index(j)
+		var elemFailures failures
+		if (elem.Kind() == reflect.Interface || elem.Kind() == reflect.Ptr) && elem.IsNil() {
+			if validation.Flags&Required > 0 {
+				elemFailures.add(FailNil, validation.getRequiredRule())
+			}
+		} else {
+			if elem.Kind() == reflect.Interface || elem.Kind() == reflect.Ptr {
+				elem = elem.Elem()
+			}
+			elemFailures = validation.validate(elem)
+		}
+
+		if len(elemFailures) > 0 {
+			if options.FailFast {
+				elemFailures = elemFailures.first()
+			}
+			valid = false
+			*fieldErrors = append(*fieldErrors, newFieldError(fmt.Sprintf("%s[%d]", fieldKey, j), elem, elemFailures, validation))
+		}
+	}
+	return valid
+}
+
+// isValidatedType checks if field of type t is validated, which are ints, floats, bool, string, slices, arrays, maps,
+// time.Time, pointers, interfaces, channels, funcs and nullable wrappers such as sql.NullString
+func isValidatedType(t reflect.Type) bool {
+	k := t.Kind()
+	return isInt(k) || isFloat(k) || k == reflect.String || k == reflect.Bool || k == reflect.Slice || k == reflect.Array || k == reflect.Map || k == reflect.Ptr || k == reflect.Interface || k == reflect.Chan || k == reflect.Func || isTime(t) || isNullable(t)
+}
+
+// isNullable checks if t is a struct that wraps a value with Valid bool field, such as sql.NullString, sql.NullInt64
+// or sql.Null[T], which is a struct with two exported fields where one of them is "Valid"
+func isNullable(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t.NumField() != 2 {
+		return false
+	}
+	valid, ok := t.FieldByName("Valid")
+	return ok && valid.Type.Kind() == reflect.Bool && t.Field(0).IsExported() && t.Field(1).IsExported()
+}
+
+// getNullableValue returns value wrapped in nullable struct (see isNullable), and true when it is null
+func getNullableValue(v reflect.Value) (reflect.Value, bool) {
+	if !v.FieldByName("Valid").Bool() {
+		return reflect.Value{}, true
+	}
+	if v.Type().Field(0).Name == "Valid" {
+		return v.Field(1), false
+	}
+	return v.Field(0), false
+}
+
+// isEmbeddedStruct checks if type of an anonymous field is a struct, or a pointer to struct, which fields are promoted
+func isEmbeddedStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && !isTime(t)
+}
+
+// getShadowedFields returns names of fields that shadow the ones in structs embedded in s
+func getShadowedFields(s reflect.Type, shadowed map[string]bool) map[string]bool {
+	embeddedShadowed := make(map[string]bool, len(shadowed)+s.NumField())
+	for name := range shadowed {
+		embeddedShadowed[name] = true
+	}
+	for j := 0; j < s.NumField(); j++ {
+		field := s.Field(j)
+		if field.Anonymous && isEmbeddedStruct(field.Type) {
+			continue
+		}
+		embeddedShadowed[field.Name] = true
+	}
+	return embeddedShadowed
+}
+
+// getNestedOptions returns ValidationOptions for validating a nested struct in field.  Options that refer to fields
+// by name are not passed, except OverwriteFieldValues and RestrictFields where keys with a path, eg.
+// "Address.ZipCode", are passed without the field name and a dot.  When field itself is in RestrictFields, all the
+// nested fields are validated.
+func getNestedOptions(options *ValidationOptions, field *reflect.StructField) *ValidationOptions {
+	fieldName := field.Name
+	restrictName := options.getRestrictName(field)
+	var restrictFields map[string]bool
+	if !options.RestrictFields[restrictName] {
+		restrictFields = getNestedPaths(options.RestrictFields, restrictName)
+	}
+
+	var overwriteFieldValues map[string]interface{}
+	for key, val := range options.OverwriteFieldValues {
+		if !strings.HasPrefix(key, fieldName+".") {
+			continue
+		}
+		if overwriteFieldValues == nil {
+			overwriteFieldValues = map[string]interface{}{}
+		}
+		overwriteFieldValues[strings.TrimPrefix(key, fieldName+".")] = val
+	}
+
+	return &ValidationOptions{
+		RestrictFields:             restrictFields,
+		ValidateWhenSuffix:         options.ValidateWhenSuffix,
+		UseTagNameInErrors:         options.UseTagNameInErrors,
+		FieldNameFunc:              options.FieldNameFunc,
+		Validators:                 options.Validators,
+		OverwriteFieldValues:       overwriteFieldValues,
+		ValidateUnexported:         options.ValidateUnexported,
+		EmailRegexp:                options.EmailRegexp,
+		FailFast:                   options.FailFast,
+		SuffixRules:                options.SuffixRules,
+		DisableBuiltinSuffixRules:  options.DisableBuiltinSuffixRules,
+		StrictTags:                 options.StrictTags,
+		OmitEmpty:                  options.OmitEmpty,
+		EmptyValues:                options.EmptyValues,
+		ValidateEmbeddedInterfaces: options.ValidateEmbeddedInterfaces,
+		cache:                      options.cache,
+		evaluatedFields:            options.evaluatedFields,
+		appliedRules:               options.appliedRules,
+	}
+}
+
+// getFieldKey returns name of a field that is used in the returned map, from FieldNameFunc when it is set
+func (o *ValidationOptions) getFieldKey(field *reflect.StructField) string {
+	if o.FieldNameFunc != nil {
+		return o.FieldNameFunc(*field)
+	}
+	return getFieldKey(field, o.UseTagNameInErrors)
+}
+
+// getRestrictName returns name of a field that is matched with RestrictFields, which is the field name unless
+// FieldNameFunc is set
+func (o *ValidationOptions) getRestrictName(field *reflect.StructField) string {
+	if o.FieldNameFunc != nil {
+		return o.FieldNameFunc(*field)
+	}
+	return field.Name
+}
+
+// getFieldKey returns name of a field that is used in the returned map.  It is the field name unless tagName is set
+// and field has such tag, eg. `json:"email,omitempty"` gives "email".
+func getFieldKey(field *reflect.StructField, tagName string) string {
+	if tagName == "" {
+		return field.Name
+	}
+
+	tagVal := strings.SplitN(field.Tag.Get(tagName), ",", 2)[0]
+	if tagVal == "" || tagVal == "-" {
+		return field.Name
+	}
+	return tagVal
 }
 
-// Validate validates fields of a struct.  Currently only fields which are string or int (any) are validated.
-// Func returns boolean value that determines whether value is true or false, and a map of fields that failed
-// validation.  See Fail* constants for the values.
-func Validate(obj interface{}, options *ValidationOptions) (bool, map[string]int) {
-	// ValidationOptions is required
-	if options == nil {
-		panic("ValidationOptions cannot be nil")
+// getFieldValue returns value of a struct field, which can be overwritten in ValidationOptions.  When
+// ValidateUnexported is set, value of an unexported field is read with unsafe so it can be used as any other value.
+func getFieldValue(structValue reflect.Value, field *reflect.StructField, options *ValidationOptions) (reflect.Value, error) {
+	overwriteVal, ok := options.OverwriteFieldValues[field.Name]
+	if ok {
+		return getOverwriteValue(overwriteVal, field)
 	}
+	fieldValue := structValue.FieldByIndex(field.Index)
+	if options.ValidateUnexported && !field.IsExported() && fieldValue.CanAddr() {
+		return reflect.NewAt(field.Type, unsafe.Pointer(fieldValue.UnsafeAddr())).Elem(), nil
+	}
+	return fieldValue, nil
+}
 
-	v := reflect.ValueOf(obj)
-	i := reflect.Indirect(v)
-	s := i.Type()
+// getOverwriteValue returns value from OverwriteFieldValues that is of the field type, or of the type it points to
+// when field is a pointer.  Value of a different type is converted only when both are strings, signed ints, unsigned
+// ints or floats, and value fits in the field type.  Otherwise an error is returned.  nil gives nil of the field type,
+// eg. nil pointer, and an error is returned when field type cannot be nil, eg. string.
+func getOverwriteValue(overwriteVal interface{}, field *reflect.StructField) (reflect.Value, error) {
+	v := reflect.ValueOf(overwriteVal)
+	if !v.IsValid() {
+		switch field.Type.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map, reflect.Chan, reflect.Func:
+			return reflect.Zero(field.Type), nil
+		}
+		return reflect.Value{}, fmt.Errorf("nil in OverwriteFieldValues cannot be used for field %s of type %s", field.Name, field.Type)
+	}
 
-	// TODO: Fix this to traverse the pointer behind reflect.Value properly.  Current this is made to support
-	// struct-db-postgres module that uses this validator.
-	if s.String() == "reflect.Value" {
-		s = reflect.ValueOf(obj.(reflect.Value).Interface()).Type().Elem().Elem()
+	t := field.Type
+	if v.Type() == t || (t.Kind() == reflect.Ptr && v.Type() == t.Elem()) || (t.Kind() == reflect.Interface && v.Type().Implements(t)) {
+		return v, nil
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
 	}
 
-	tagName := "validation"
-	if options.OverwriteTagName != "" {
-		tagName = options.OverwriteTagName
+	vk := v.Kind()
+	tk := t.Kind()
+	convertible := false
+	switch {
+	case vk == reflect.String && tk == reflect.String:
+		convertible = true
+	case isSignedInt(vk) && isSignedInt(tk):
+		convertible = !reflect.Zero(t).OverflowInt(v.Int())
+	case isUint(vk) && isUint(tk):
+		convertible = !reflect.Zero(t).OverflowUint(v.Uint())
+	case isFloat(vk) && isFloat(tk):
+		convertible = !reflect.Zero(t).OverflowFloat(v.Float())
+	}
+	if !convertible {
+		return reflect.Value{}, fmt.Errorf("value of type %s in OverwriteFieldValues cannot be used for field %s of type %s", v.Type(), field.Name, field.Type)
 	}
+	return v.Convert(t), nil
+}
 
-	invalidFields := make(map[string]int, s.NumField())
-	valid := true
+// isEqualValue compares two values of string, bool, int (any), uint (any) or float (any) kind
+func isEqualValue(a reflect.Value, b reflect.Value) bool {
+	switch {
+	case !a.IsValid() || !b.IsValid():
+		return a.IsValid() == b.IsValid()
+	case a.Kind() == reflect.String && b.Kind() == reflect.String:
+		return a.String() == b.String()
+	case a.Kind() == reflect.Bool && b.Kind() == reflect.Bool:
+		return a.Bool() == b.Bool()
+	case isSignedInt(a.Kind()) && isSignedInt(b.Kind()):
+		return a.Int() == b.Int()
+	case isUint(a.Kind()) && isUint(b.Kind()):
+		return a.Uint() == b.Uint()
+	case isSignedInt(a.Kind()) && isUint(b.Kind()):
+		return a.Int() >= 0 && uint64(a.Int()) == b.Uint()
+	case isUint(a.Kind()) && isSignedInt(b.Kind()):
+		return b.Int() >= 0 && a.Uint() == uint64(b.Int())
+	case isFloat(a.Kind()) && isFloat(b.Kind()):
+		return a.Float() == b.Float()
+	}
+	return false
+}
 
-	for j := 0; j < s.NumField(); j++ {
-		field := s.Field(j)
-		fieldKind := field.Type.Kind()
+// compareWithFields compares a numeric value with the fields set in gtfield, gtefield, ltfield, ltefield and
+// withinfield, and returns FailGtField, FailLtField and FailWithinField failures for the ones that failed.  An error is
+// returned when referenced field does not exist or any of the values is not a number.
+func compareWithFields(structValue reflect.Value, fieldValue reflect.Value, v *ValueValidation, options *ValidationOptions) (failures, error) {
+	if v.GtField == "" && v.GteField == "" && v.LtField == "" && v.LteField == "" && v.WithinField == "" {
+		return nil, nil
+	}
+
+	comparisons := []struct {
+		rule      string
+		fieldName string
+		failFlag  int64
+		isValid   func(cmp int) bool
+	}{
+		{"gtfield", v.GtField, FailGtField, func(cmp int) bool { return cmp > 0 }},
+		{"gtefield", v.GteField, FailGtField, func(cmp int) bool { return cmp >= 0 }},
+		{"ltfield", v.LtField, FailLtField, func(cmp int) bool { return cmp < 0 }},
+		{"ltefield", v.LteField, FailLtField, func(cmp int) bool { return cmp <= 0 }},
+	}
 
-		// check if only specified field should be checked
-		if len(options.RestrictFields) > 0 && !options.RestrictFields[field.Name] {
+	var f failures
+	for _, comparison := range comparisons {
+		if comparison.fieldName == "" {
 			continue
 		}
+		otherField, exists := structValue.Type().FieldByName(comparison.fieldName)
+		if !exists {
+			return nil, fmt.Errorf("field %s referenced in %s does not exist", comparison.fieldName, comparison.rule)
+		}
+		otherValue, err := getFieldValue(structValue, &otherField, options)
+		if err != nil {
+			return nil, err
+		}
+		otherValue = reflect.Indirect(otherValue)
+		cmp, comparable := compareNumbers(fieldValue, otherValue)
+		if !comparable {
+			return nil, fmt.Errorf("field %s referenced in %s cannot be compared as a number", comparison.fieldName, comparison.rule)
+		}
+		if !comparison.isValid(cmp) {
+			f.add(comparison.failFlag, comparison.rule)
+		}
+	}
 
-		// validate only ints and string
-		if !isInt(fieldKind) && fieldKind != reflect.String {
-			continue
+	if v.WithinField != "" {
+		otherField, exists := structValue.Type().FieldByName(v.WithinField)
+		if !exists {
+			return nil, fmt.Errorf("field %s referenced in withinfield does not exist", v.WithinField)
+		}
+		otherValue, err := getFieldValue(structValue, &otherField, options)
+		if err != nil {
+			return nil, err
+		}
+		delta, comparable := getDelta(fieldValue, reflect.Indirect(otherValue))
+		if !comparable {
+			return nil, fmt.Errorf("field %s referenced in withinfield cannot be compared as a number", v.WithinField)
+		}
+		if delta > v.WithinDelta {
+			f.add(FailWithinField, "withinfield")
 		}
+	}
+	return f, nil
+}
 
-		validation := NewValueValidation()
+// getDelta returns absolute difference between two numbers, or two time.Time values in seconds.  Second returned value
+// is false when values are neither numbers nor time.Time.
+func getDelta(a reflect.Value, b reflect.Value) (float64, bool) {
+	if a.IsValid() && b.IsValid() && isTime(a.Type()) && isTime(b.Type()) {
+		if !a.CanInterface() || !b.CanInterface() {
+			return 0, false
+		}
+		return math.Abs(a.Interface().(time.Time).Sub(b.Interface().(time.Time)).Seconds()), true
+	}
+	if _, comparable := compareNumbers(a, b); !comparable {
+		return 0, false
+	}
+	return math.Abs(toFloat(a) - toFloat(b)), true
+}
+
+// compareNumbers compares two values of int (any), uint (any) or float (any) kind, and returns -1, 0 or 1 when a is
+// less than, equal to or greater than b.  Second returned value is false when any of the values is not a number.
+func compareNumbers(a reflect.Value, b reflect.Value) (int, bool) {
+	if !a.IsValid() || !b.IsValid() {
+		return 0, false
+	}
 
-		tagVal, tagRegexpVal := getFieldTagValues(&field, tagName, options.OverwriteFieldTags)
-		setValidationFromTags(validation, tagVal, tagRegexpVal)
-		if options.ValidateWhenSuffix {
-			setValidationFromSuffix(validation, &field)
+	ak := a.Kind()
+	bk := b.Kind()
+	switch {
+	case isSignedInt(ak) && isSignedInt(bk):
+		return cmpOrdered(a.Int(), b.Int()), true
+	case isUint(ak) && isUint(bk):
+		return cmpOrdered(a.Uint(), b.Uint()), true
+	case isSignedInt(ak) && isUint(bk):
+		if a.Int() < 0 {
+			return -1, true
+		}
+		return cmpOrdered(uint64(a.Int()), b.Uint()), true
+	case isUint(ak) && isSignedInt(bk):
+		if b.Int() < 0 {
+			return 1, true
 		}
+		return cmpOrdered(a.Uint(), uint64(b.Int())), true
+	case (isInt(ak) || isFloat(ak)) && (isInt(bk) || isFloat(bk)):
+		return cmpOrdered(toFloat(a), toFloat(b)), true
+	}
+	return 0, false
+}
+
+// cmpOrdered returns -1, 0 or 1 when a is less than, equal to or greater than b
+func cmpOrdered[T int64 | uint64 | float64](a T, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// toFloat returns value of int (any), uint (any) or float (any) kind as float64
+func toFloat(v reflect.Value) float64 {
+	switch {
+	case isSignedInt(v.Kind()):
+		return float64(v.Int())
+	case isUint(v.Kind()):
+		return float64(v.Uint())
+	}
+	return v.Float()
+}
+
+// fieldCacheKey identifies a struct field and options used to parse its validation
+type fieldCacheKey struct {
+	structType                reflect.Type
+	index                     int
+	tagName                   string
+	validateWhenSuffix        bool
+	disableBuiltinSuffixRules bool
+	strictTags                bool
+}
+
+// validationCache contains ValueValidation of struct fields parsed from their tags, keyed by fieldCacheKey, and
+// compiled regular expressions from tags, keyed by pattern
+type validationCache struct {
+	fields  sync.Map
+	regexps sync.Map
+}
+
+// defaultCache is used when validation is not done with a Validator
+var defaultCache = &validationCache{}
+
+// getCache returns cache of the Validator that passed options, or the default one
+func getCache(options *ValidationOptions) *validationCache {
+	if options.cache != nil {
+		return options.cache
+	}
+	return defaultCache
+}
+
+// getFieldValidation returns ValueValidation of a struct field.  Unless the field tags are overwritten in
+// ValidationOptions, it is cached so the tags of a struct type are parsed only once.  Returned value must not be
+// modified.
+func getFieldValidation(s reflect.Type, index int, tagName string, options *ValidationOptions) (*ValueValidation, error) {
+	field := s.Field(index)
+	_, overwritten := options.OverwriteFieldTags[field.Name]
+
+	// rules from SuffixRules depend on options so such fields are not cached
+	suffixRules := getSuffixRules(&field, options)
+	if len(suffixRules) > 0 {
+		overwritten = true
+	}
 
-		// field value can be overwritten in ValidationOptions
-		var fieldValue reflect.Value
-		overwriteVal, ok := options.OverwriteFieldValues[field.Name]
+	key := fieldCacheKey{
+		structType:                s,
+		index:                     index,
+		tagName:                   tagName,
+		validateWhenSuffix:        options.ValidateWhenSuffix,
+		disableBuiltinSuffixRules: options.DisableBuiltinSuffixRules,
+		strictTags:                options.StrictTags,
+	}
+	cache := getCache(options)
+	if !overwritten {
+		cached, ok := cache.fields.Load(key)
 		if ok {
-			fieldValue = reflect.ValueOf(overwriteVal)
-		} else {
-			fieldValue = v.Elem().FieldByName(field.Name)
+			return cached.(*ValueValidation), nil
 		}
+	}
 
-		ok, failureFlags := validation.ValidateReflectValue(fieldValue)
-		if !ok {
-			valid = false
-			invalidFields[field.Name] = failureFlags
+	validation := NewValueValidation()
+
+	tagVal, tagRegexpVal, tagRegexpNotVal, tagKeysVal, tagValuesVal, tagElemVal, tagMsgVal, tagDateTimeVal := getFieldTagValues(&field, tagName, options.OverwriteFieldTags)
+	if options.StrictTags {
+		for _, tag := range append([]string{tagVal, tagKeysVal, tagValuesVal, tagElemVal}, suffixRules...) {
+			err := checkTagTokens(tag)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 
-	return valid, invalidFields
+	err := setValidationFromTags(validation, tagVal, tagRegexpVal, tagRegexpNotVal, cache)
+	if err != nil {
+		return nil, err
+	}
+	validation.Message = tagMsgVal
+	if tagDateTimeVal != "" {
+		validation.DateTime = tagDateTimeVal
+	}
+
+	// keys and values of a map have their own validation
+	if field.Type.Kind() == reflect.Map && tagKeysVal != "" {
+		validation.Keys = NewValueValidation()
+		err = setValidationFromTags(validation.Keys, tagKeysVal, "", "", cache)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s_keys tag: %w", tagName, err)
+		}
+	}
+	if field.Type.Kind() == reflect.Map && tagValuesVal != "" {
+		validation.Values = NewValueValidation()
+		err = setValidationFromTags(validation.Values, tagValuesVal, "", "", cache)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s_values tag: %w", tagName, err)
+		}
+	}
+	// elements of a slice or an array have their own validation as well
+	if (field.Type.Kind() == reflect.Slice || field.Type.Kind() == reflect.Array) && tagElemVal != "" {
+		validation.Elements = NewValueValidation()
+		err = setValidationFromTags(validation.Elements, tagElemVal, "", "", cache)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s_elem tag: %w", tagName, err)
+		}
+	}
+	if options.ValidateWhenSuffix && !options.DisableBuiltinSuffixRules {
+		setValidationFromSuffix(validation, &field)
+	}
+	for _, suffixRule := range suffixRules {
+		err := setValidationFromTags(validation, suffixRule, "", "", cache)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule in SuffixRules: %w", err)
+		}
+	}
+
+	if !overwritten {
+		cache.fields.Store(key, validation)
+	}
+	return validation, nil
+}
+
+// tagTokens and tagTokenPrefixes contain rules that can be used in validation tag, including the ones used by
+// GenerateHTML.  They are used to check tags when StrictTags option is set.
+var tagTokens = []string{"req", "reqtrim", "notblank", "email", "url", "uuid", "uuid:v4", "ip", "ip:v4", "ip:v6", "cidr", "base64", "base64url", "hex", "json", "creditcard", "hostname", "fqdn", "semver", "semver:v", "trim", "alpha", "numeric", "alphanumeric", "lowercase", "uppercase", "numericrange", "after:now", "before:now", "istrue", "isfalse", "omitempty", "runelen", "positive", "nonneg", "negative", "nonpositive", "percent", "percentfloat", "uitextarea", "uipassword"}
+var tagTokenPrefixes = []string{"lenmin:", "lenmax:", "len:", "digitsmin:", "digitsmax:", "digits:", "decimal:", "valmin:", "valmax:", "between:", "gt:", "gte:", "lt:", "lte:", "ranges:", "multipleof:", "regexp:", "regexpnot:", "oneof:", "oneofci:", "oneofint:", "prefix:", "suffix:", "contains:", "excludes:", "charset:", "charsetnot:", "datetime:", "eqfield:", "gtfield:", "gtefield:", "ltfield:", "ltefield:", "withinfield:", "required_with:", "required_without:", "custom:"}
+
+// checkTagTokens returns an error when tag contains a rule that is not known
+func checkTagTokens(tag string) error {
+	for _, opt := range strings.Split(tag, " ") {
+		if opt == "" || isKnownTagToken(opt) {
+			continue
+		}
+		return fmt.Errorf("unknown rule '%s' in tag '%s', known rules are: %s, and ones with a value: %s", opt, tag, strings.Join(tagTokens, ", "), strings.Join(tagTokenPrefixes, ", "))
+	}
+	return nil
 }
 
-func setValidationFromTags(v *ValueValidation, tag string, tagRegexp string) {
+func isKnownTagToken(opt string) bool {
+	for _, token := range tagTokens {
+		if opt == token {
+			return true
+		}
+	}
+	for _, prefix := range tagTokenPrefixes {
+		if strings.HasPrefix(opt, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func setValidationFromTags(v *ValueValidation, tag string, tagRegexp string, tagRegexpNot string, cache *validationCache) error {
 	opts := strings.SplitN(tag, " ", -1)
 	for _, opt := range opts {
 		if opt == "req" {
 			v.Flags = v.Flags | Required
 		}
+		// reqtrim is req where string containing only whitespace is considered empty
+		if opt == "reqtrim" {
+			v.Flags = v.Flags | Required | ReqTrim
+		}
+		// omitempty makes zero value of an optional field not validated
+		if opt == "omitempty" {
+			v.Flags = v.Flags | OmitEmpty
+		}
+		// notblank is the same as reqtrim
+		if opt == "notblank" {
+			v.Flags = v.Flags | Required | ReqTrim | NotBlank
+		}
 		if opt == "email" {
 			v.Flags = v.Flags | Email
 		}
-		for _, valOpt := range []string{"lenmin", "lenmax", "valmin", "valmax", "regexp"} {
+		if opt == "trim" {
+			v.Flags = v.Flags | Trim
+		}
+		if opt == "alpha" {
+			v.Flags = v.Flags | Alpha
+		}
+		if opt == "numeric" {
+			v.Flags = v.Flags | Numeric
+		}
+		if opt == "alphanumeric" {
+			v.Flags = v.Flags | Alphanumeric
+		}
+		if opt == "after:now" {
+			v.Flags = v.Flags | TimeAfterNow
+		}
+		if opt == "before:now" {
+			v.Flags = v.Flags | TimeBeforeNow
+		}
+		if opt == "numericrange" {
+			v.Flags = v.Flags | NumericRange
+		}
+		if opt == "lowercase" {
+			v.Flags = v.Flags | Lowercase
+		}
+		if opt == "uppercase" {
+			v.Flags = v.Flags | Uppercase
+		}
+		if opt == "url" {
+			v.Flags = v.Flags | URL
+		}
+		if opt == "uuid" {
+			v.Flags = v.Flags | UUID
+		}
+		if opt == "uuid:v4" {
+			v.Flags = v.Flags | UUIDv4
+		}
+		if opt == "ip" {
+			v.Flags = v.Flags | IP
+		}
+		if opt == "ip:v4" {
+			v.Flags = v.Flags | IPv4
+		}
+		if opt == "ip:v6" {
+			v.Flags = v.Flags | IPv6
+		}
+		if opt == "cidr" {
+			v.Flags = v.Flags | CIDR
+		}
+		if opt == "base64" {
+			v.Flags = v.Flags | Base64
+		}
+		if opt == "base64url" {
+			v.Flags = v.Flags | Base64URL
+		}
+		if opt == "hex" {
+			v.Flags = v.Flags | Hex
+		}
+		if opt == "json" {
+			v.Flags = v.Flags | JSON
+		}
+		if opt == "creditcard" {
+			v.Flags = v.Flags | CreditCard
+		}
+		if opt == "hostname" {
+			v.Flags = v.Flags | Hostname
+		}
+		if opt == "fqdn" {
+			v.Flags = v.Flags | FQDN
+		}
+		if opt == "semver" {
+			v.Flags = v.Flags | SemVer
+		}
+		if opt == "semver:v" {
+			v.Flags = v.Flags | SemVer | SemVerPrefix
+		}
+		// runelen makes lenmin, lenmax and len of a string count characters instead of bytes
+		if opt == "runelen" {
+			v.Flags = v.Flags | RuneLen
+		}
+		// numbers greater than, at least, less than and at most zero
+		if opt == "positive" {
+			v.Flags = v.Flags | Positive
+		}
+		if opt == "nonneg" {
+			v.Flags = v.Flags | NonNegative
+		}
+		if opt == "negative" {
+			v.Flags = v.Flags | Negative
+		}
+		if opt == "nonpositive" {
+			v.Flags = v.Flags | NonPositive
+		}
+		// values in oneof are separated with comma as the whole tag is split by space
+		if strings.HasPrefix(opt, "oneof:") {
+			v.OneOf = strings.Split(strings.Replace(opt, "oneof:", "", 1), ",")
+			continue
+		}
+		// ranges are separated with comma, and minimum and maximum of a range with a hyphen, eg. "200-299,-10--1"
+		if strings.HasPrefix(opt, "ranges:") {
+			for _, r := range strings.Split(strings.Replace(opt, "ranges:", "", 1), ",") {
+				bounds := rangeRegex.FindStringSubmatch(r)
+				if bounds == nil {
+					return fmt.Errorf("invalid range '%s' in '%s'", r, opt)
+				}
+				valMin, errMin := strconv.ParseInt(bounds[1], 10, 64)
+				valMax, errMax := strconv.ParseInt(bounds[2], 10, 64)
+				if errMin != nil || errMax != nil || valMin > valMax {
+					return fmt.Errorf("invalid range '%s' in '%s'", r, opt)
+				}
+				v.Ranges = append(v.Ranges, [2]int64{valMin, valMax})
+			}
+			continue
+		}
+		// gt, gte, lt and lte compare a number with a bound, and only one of gt and gte, and lt and lte can be used
+		if strings.HasPrefix(opt, "gt:") || strings.HasPrefix(opt, "gte:") || strings.HasPrefix(opt, "lt:") || strings.HasPrefix(opt, "lte:") {
+			op, val, _ := strings.Cut(opt, ":")
+			f, err := strconv.ParseFloat(val, 64)
+			if err != nil || math.IsNaN(f) {
+				return fmt.Errorf("invalid %s '%s': bound must be a number", op, opt)
+			}
+			switch op {
+			case "gt":
+				v.Gt = f
+				v.Flags = (v.Flags &^ Gte) | Gt
+			case "gte":
+				v.Gt = f
+				v.Flags = (v.Flags &^ Gt) | Gte
+			case "lt":
+				v.Lt = f
+				v.Flags = (v.Flags &^ Lte) | Lt
+			case "lte":
+				v.Lt = f
+				v.Flags = (v.Flags &^ Lt) | Lte
+			}
+			continue
+		}
+		// multipleof cannot be zero, as no number is a multiple of it
+		if strings.HasPrefix(opt, "multipleof:") {
+			n, err := strconv.ParseFloat(strings.Replace(opt, "multipleof:", "", 1), 64)
+			if err != nil || n == 0 || math.IsInf(n, 0) || math.IsNaN(n) {
+				return fmt.Errorf("invalid multipleof '%s': it must be a non-zero number", opt)
+			}
+			v.MultipleOf = n
+			continue
+		}
+		// oneofci is oneof where strings are compared case-insensitively
+		if strings.HasPrefix(opt, "oneofci:") {
+			v.OneOf = strings.Split(strings.Replace(opt, "oneofci:", "", 1), ",")
+			v.Flags = v.Flags | OneOfCI
+			continue
+		}
+		// percent is a shorthand for valmin:0 valmax:100, so that zero is a valid percentage
+		if opt == "percent" {
+			err := setValidationFromTags(v, "valmin:0 valmax:100", "", "", cache)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		// percentfloat sets the float bounds only, as it is for float fields, eg. 99.5
+		if opt == "percentfloat" {
+			v.ValMinFloat = 0
+			v.ValMaxFloat = 100
+			v.Flags = v.Flags | ValMinNotNil | PercentFloat
+			continue
+		}
+		// between is a shorthand for valmin and valmax, with bounds separated with comma
+		if strings.HasPrefix(opt, "between:") {
+			bounds := strings.Split(strings.Replace(opt, "between:", "", 1), ",")
+			if len(bounds) != 2 {
+				return fmt.Errorf("invalid between '%s': two bounds are required", opt)
+			}
+			valMin, errMin := strconv.ParseFloat(bounds[0], 64)
+			valMax, errMax := strconv.ParseFloat(bounds[1], 64)
+			if errMin != nil || errMax != nil {
+				return fmt.Errorf("invalid between '%s': bounds must be numbers", opt)
+			}
+			if valMin > valMax {
+				return fmt.Errorf("invalid between '%s': minimum is greater than maximum", opt)
+			}
+			err := setValidationFromTags(v, "valmin:"+bounds[0]+" valmax:"+bounds[1], "", "", cache)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		// values in oneofint are separated with comma as well, and they must be valid ints
+		if strings.HasPrefix(opt, "oneofint:") {
+			for _, val := range strings.Split(strings.Replace(opt, "oneofint:", "", 1), ",") {
+				i, err := strconv.ParseInt(val, 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid oneofint value '%s': %w", val, err)
+				}
+				v.OneOfInt = append(v.OneOfInt, i)
+			}
+			continue
+		}
+		// prefix and suffix cannot contain spaces as the whole tag is split by space
+		if strings.HasPrefix(opt, "prefix:") {
+			v.Prefix = strings.Replace(opt, "prefix:", "", 1)
+			continue
+		}
+		if strings.HasPrefix(opt, "suffix:") {
+			v.Suffix = strings.Replace(opt, "suffix:", "", 1)
+			continue
+		}
+		// substrings in contains and excludes cannot contain spaces, tag can be repeated to check many substrings
+		if strings.HasPrefix(opt, "contains:") {
+			v.Contains = append(v.Contains, strings.Replace(opt, "contains:", "", 1))
+			continue
+		}
+		if strings.HasPrefix(opt, "excludes:") {
+			v.Excludes = append(v.Excludes, strings.Replace(opt, "excludes:", "", 1))
+			continue
+		}
+		// layout cannot contain spaces as the whole tag is split by space, "validation_datetime" tag can be used instead
+		if strings.HasPrefix(opt, "datetime:") {
+			v.DateTime = strings.Replace(opt, "datetime:", "", 1)
+			continue
+		}
+		// withinfield contains field name and the maximal difference separated with comma
+		if strings.HasPrefix(opt, "withinfield:") {
+			args := strings.Split(strings.Replace(opt, "withinfield:", "", 1), ",")
+			if len(args) != 2 || args[0] == "" {
+				return fmt.Errorf("invalid withinfield '%s': field name and delta are required", opt)
+			}
+			delta, err := strconv.ParseFloat(args[1], 64)
+			if err != nil || delta < 0 {
+				return fmt.Errorf("invalid withinfield '%s': delta must be a non-negative number", opt)
+			}
+			v.WithinField = args[0]
+			v.WithinDelta = delta
+			continue
+		}
+		// characters in charset and charsetnot cannot contain space as the whole tag is split by space
+		if strings.HasPrefix(opt, "charset:") {
+			v.Charset = strings.Replace(opt, "charset:", "", 1)
+			continue
+		}
+		if strings.HasPrefix(opt, "charsetnot:") {
+			v.CharsetNot = strings.Replace(opt, "charsetnot:", "", 1)
+			continue
+		}
+		if strings.HasPrefix(opt, "gtfield:") {
+			v.GtField = strings.Replace(opt, "gtfield:", "", 1)
+			continue
+		}
+		if strings.HasPrefix(opt, "gtefield:") {
+			v.GteField = strings.Replace(opt, "gtefield:", "", 1)
+			continue
+		}
+		if strings.HasPrefix(opt, "ltfield:") {
+			v.LtField = strings.Replace(opt, "ltfield:", "", 1)
+			continue
+		}
+		if strings.HasPrefix(opt, "ltefield:") {
+			v.LteField = strings.Replace(opt, "ltefield:", "", 1)
+			continue
+		}
+		if strings.HasPrefix(opt, "eqfield:") {
+			v.EqField = strings.Replace(opt, "eqfield:", "", 1)
+			continue
+		}
+		if strings.HasPrefix(opt, "required_without:") {
+			v.RequiredWithout = strings.Split(strings.Replace(opt, "required_without:", "", 1), ",")
+			continue
+		}
+		if strings.HasPrefix(opt, "required_with:") {
+			v.RequiredWith = strings.Split(strings.Replace(opt, "required_with:", "", 1), ",")
+			continue
+		}
+		if strings.HasPrefix(opt, "custom:") {
+			v.Custom = append(v.Custom, strings.Replace(opt, "custom:", "", 1))
+			continue
+		}
+		if opt == "istrue" {
+			v.Flags = v.Flags | IsTrue
+		}
+		if opt == "isfalse" {
+			v.Flags = v.Flags | IsFalse
+		}
+		for _, valOpt := range []string{"lenmin", "lenmax", "len", "digitsmin", "digitsmax", "digits", "decimal", "valmin", "valmax", "regexp", "regexpnot"} {
 			if strings.HasPrefix(opt, valOpt+":") {
 				val := strings.Replace(opt, valOpt+":", "", 1)
-				if valOpt == "regexp" {
-					v.Regexp = regexp.MustCompile(val)
+				if valOpt == "regexp" || valOpt == "regexpnot" {
+					re, err := cache.compileRegexp(val)
+					if err != nil {
+						return fmt.Errorf("invalid regexp '%s': %w", val, err)
+					}
+					if valOpt == "regexp" {
+						v.Regexp = re
+					} else {
+						v.RegexpNot = re
+					}
 					continue
 				}
 
+				// valmin and valmax are parsed as floats as well so that they can be used with float fields
+				if valOpt == "valmin" || valOpt == "valmax" {
+					f, err := strconv.ParseFloat(val, 64)
+					if err != nil {
+						continue
+					}
+					if valOpt == "valmin" {
+						v.ValMinFloat = f
+						if f == 0 {
+							v.Flags = v.Flags | ValMinNotNil
+						}
+					} else {
+						v.ValMaxFloat = f
+						if f == 0 {
+							v.Flags = v.Flags | ValMaxNotNil
+						}
+					}
+				}
+
+				// and as unsigned ints so that bounds greater than math.MaxInt64 can be used with uint fields
+				if valOpt == "valmin" || valOpt == "valmax" {
+					u, err := strconv.ParseUint(val, 10, 64)
+					if err == nil && valOpt == "valmin" {
+						v.ValMinUint = u
+					}
+					if err == nil && valOpt == "valmax" {
+						v.ValMaxUint = u
+					}
+				}
+
 				i, err := strconv.Atoi(val)
 				if err != nil {
 					continue
@@ -128,6 +2217,16 @@ func setValidationFromTags(v *ValueValidation, tag string, tagRegexp string) {
 					v.LenMin = i
 				case "lenmax":
 					v.LenMax = i
+				case "len":
+					v.Len = i
+				case "digitsmin":
+					v.DigitsMin = i
+				case "digitsmax":
+					v.DigitsMax = i
+				case "digits":
+					v.Digits = i
+				case "decimal":
+					v.Decimal = i
 				case "valmin":
 					v.ValMin = int64(i)
 					if i == 0 {
@@ -144,14 +2243,74 @@ func setValidationFromTags(v *ValueValidation, tag string, tagRegexp string) {
 	}
 
 	if tagRegexp != "" {
-		v.Regexp = regexp.MustCompile(tagRegexp)
+		re, err := cache.compileRegexp(tagRegexp)
+		if err != nil {
+			return fmt.Errorf("invalid regexp '%s': %w", tagRegexp, err)
+		}
+		v.Regexp = re
+	}
+
+	if tagRegexpNot != "" {
+		re, err := cache.compileRegexp(tagRegexpNot)
+		if err != nil {
+			return fmt.Errorf("invalid regexp '%s': %w", tagRegexpNot, err)
+		}
+		v.RegexpNot = re
+	}
+
+	return nil
+}
+
+// rangeRegex matches a range in ranges tag, eg. "200-299" or "-10--1"
+var rangeRegex = regexp.MustCompile("^(-?[0-9]+)-(-?[0-9]+)$")
+
+// compileRegexp compiles regular expression or gets it from the cache when it has been compiled already
+func (c *validationCache) compileRegexp(pattern string) (*regexp.Regexp, error) {
+	cached, ok := c.regexps.Load(pattern)
+	if ok {
+		return cached.(*regexp.Regexp), nil
 	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	c.regexps.Store(pattern, re)
+	return re, nil
+}
+
+// getSuffixRules returns rules from SuffixRules for suffixes that field name ends with, sorted by suffix.  Nothing is
+// returned when ValidateWhenSuffix is not set.
+func getSuffixRules(field *reflect.StructField, options *ValidationOptions) []string {
+	if !options.ValidateWhenSuffix || len(options.SuffixRules) == 0 {
+		return nil
+	}
+
+	var suffixes []string
+	for suffix := range options.SuffixRules {
+		if strings.HasSuffix(field.Name, suffix) {
+			suffixes = append(suffixes, suffix)
+		}
+	}
+	sort.Strings(suffixes)
+
+	rules := make([]string, 0, len(suffixes))
+	for _, suffix := range suffixes {
+		rules = append(rules, options.SuffixRules[suffix])
+	}
+	return rules
 }
 
 func setValidationFromSuffix(v *ValueValidation, field *reflect.StructField) {
 	if strings.HasSuffix(field.Name, "Email") {
 		v.Flags = v.Flags | Email
 	}
+	if strings.HasSuffix(field.Name, "URL") {
+		v.Flags = v.Flags | URL
+	}
+	if strings.HasSuffix(field.Name, "Host") {
+		v.Flags = v.Flags | Hostname
+	}
 	if strings.HasSuffix(field.Name, "Price") && v.ValMin == 0 && v.ValMax == 0 && v.Flags&ValMinNotNil == 0 && v.Flags&ValMaxNotNil == 0 {
 		v.ValMin = 0
 		v.Flags = v.Flags | ValMinNotNil
@@ -159,26 +2318,54 @@ func setValidationFromSuffix(v *ValueValidation, field *reflect.StructField) {
 }
 
 func isInt(k reflect.Kind) bool {
-	if k == reflect.Int64 || k == reflect.Int32 || k == reflect.Int16 || k == reflect.Int8 || k == reflect.Int || k == reflect.Uint64 || k == reflect.Uint32 || k == reflect.Uint16 || k == reflect.Uint8 || k == reflect.Uint {
+	return isSignedInt(k) || isUint(k)
+}
+
+func isSignedInt(k reflect.Kind) bool {
+	if k == reflect.Int64 || k == reflect.Int32 || k == reflect.Int16 || k == reflect.Int8 || k == reflect.Int {
+		return true
+	}
+	return false
+}
+
+func isUint(k reflect.Kind) bool {
+	if k == reflect.Uint64 || k == reflect.Uint32 || k == reflect.Uint16 || k == reflect.Uint8 || k == reflect.Uint {
+		return true
+	}
+	return false
+}
+
+func isFloat(k reflect.Kind) bool {
+	if k == reflect.Float64 || k == reflect.Float32 {
 		return true
 	}
 	return false
 }
 
-func getFieldTagValues(field *reflect.StructField, tagName string, overwriteFieldTags map[string]map[string]string) (tagVal string, tagRegexpVal string) {
-	tagVal = field.Tag.Get(tagName)
-	tagRegexpVal = field.Tag.Get(tagName + "_regexp")
+var timeType = reflect.TypeOf(time.Time{})
 
-	overwriteTags, ok := overwriteFieldTags[field.Name]
+func isTime(t reflect.Type) bool {
+	return t == timeType
+}
+
+func getFieldTagValues(field *reflect.StructField, tagName string, overwriteFieldTags map[string]map[string]string) (tagVal string, tagRegexpVal string, tagRegexpNotVal string, tagKeysVal string, tagValuesVal string, tagElemVal string, tagMsgVal string, tagDateTimeVal string) {
+	overwriteTags := overwriteFieldTags[field.Name]
+	tagVal = getFieldTagValue(field, tagName, overwriteTags)
+	tagRegexpVal = getFieldTagValue(field, tagName+"_regexp", overwriteTags)
+	tagRegexpNotVal = getFieldTagValue(field, tagName+"_regexpnot", overwriteTags)
+	tagKeysVal = getFieldTagValue(field, tagName+"_keys", overwriteTags)
+	tagValuesVal = getFieldTagValue(field, tagName+"_values", overwriteTags)
+	tagElemVal = getFieldTagValue(field, tagName+"_elem", overwriteTags)
+	tagMsgVal = getFieldTagValue(field, tagName+"_msg", overwriteTags)
+	tagDateTimeVal = getFieldTagValue(field, tagName+"_datetime", overwriteTags)
+	return
+}
+
+// getFieldTagValue returns value of field tag, or its overwrite when there is one
+func getFieldTagValue(field *reflect.StructField, tagName string, overwriteTags map[string]string) string {
+	overwriteTagVal, ok := overwriteTags[tagName]
 	if ok {
-		overwriteTagVal, ok2 := overwriteTags[tagName]
-		if ok2 {
-			tagVal = overwriteTagVal
-		}
-		overwriteTagVal, ok2 = overwriteTags[tagName+"_regexp"]
-		if ok2 {
-			tagRegexpVal = overwriteTagVal
-		}
+		return overwriteTagVal
 	}
-	return
+	return field.Tag.Get(tagName)
 }
@@ -0,0 +1,33 @@
+package structvalidator
+
+// Validator holds ValidationOptions that are used for every validation, and its own cache of parsed tags and compiled
+// regular expressions, so that validators configured differently do not share anything.  Package-level funcs, such
+// as Validate, use a default cache shared between them.  Validator must not be copied after first use.
+type Validator struct {
+	Options ValidationOptions
+	cache   validationCache
+}
+
+// NewValidator returns a Validator with options
+func NewValidator(options ValidationOptions) *Validator {
+	return &Validator{
+		Options: options,
+	}
+}
+
+// Validate works the same as package-level Validate with the Validator options
+func (v *Validator) Validate(obj interface{}) (bool, map[string]int) {
+	return Validate(obj, v.getOptions())
+}
+
+// ValidateE works the same as package-level ValidateE with the Validator options
+func (v *Validator) ValidateE(obj interface{}) (bool, map[string]int, error) {
+	return ValidateE(obj, v.getOptions())
+}
+
+// getOptions returns a copy of the Validator options that refers to its cache
+func (v *Validator) getOptions() *ValidationOptions {
+	options := v.Options
+	options.cache = &v.cache
+	return &options
+}
@@ -0,0 +1,58 @@
+package structvalidator
+
+import (
+	"reflect"
+	"testing"
+)
+
+type TestValidatorTags struct {
+	Name  string `validation:"req lenmin:5" other:"lenmax:3"`
+	Email string `validation:"email" other:"req" other_regexp:"^[a-z]+$"`
+}
+
+func TestValidatorsWithDifferentTagNames(t *testing.T) {
+	s := TestValidatorTags{
+		Name:  "John",
+		Email: "john@example.com",
+	}
+
+	validator := NewValidator(ValidationOptions{})
+	otherValidator := NewValidator(ValidationOptions{
+		OverwriteTagName: "other",
+	})
+
+	for j := 0; j < 2; j++ {
+		valid, failedFields := validator.Validate(&s)
+		if valid || !reflect.DeepEqual(failedFields, map[string]int{"Name": FailLenMin}) {
+			t.Errorf("Validator returned invalid result: %v", failedFields)
+		}
+
+		valid, failedFields = otherValidator.Validate(&s)
+		if valid || !reflect.DeepEqual(failedFields, map[string]int{"Name": FailLenMax, "Email": FailRegexp}) {
+			t.Errorf("Validator with other tag name returned invalid result: %v", failedFields)
+		}
+	}
+
+	// each validator has its own cache
+	otherValidator.cache.fields.Range(func(key, value interface{}) bool {
+		if key.(fieldCacheKey).tagName != "other" {
+			t.Errorf("Validator cache contains field parsed by another validator")
+		}
+		return true
+	})
+	if _, ok := validator.cache.regexps.Load("^[a-z]+$"); ok {
+		t.Errorf("Validator cache contains regular expression compiled by another validator")
+	}
+}
+
+func TestValidatorWithInvalidTag(t *testing.T) {
+	s := struct {
+		Name string `validation:"regexp:[a-z"`
+	}{}
+
+	validator := NewValidator(ValidationOptions{})
+	_, _, err := validator.ValidateE(&s)
+	if err == nil {
+		t.Errorf("Validator did not return an error for invalid regular expression")
+	}
+}
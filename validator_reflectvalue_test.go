@@ -29,7 +29,7 @@ func TestWithInvalidValuesOnReflectValue(t *testing.T) {
 		}
 
 		expectedBool := false
-		expectedFailedFields := map[string]int{
+		expectedFailedFields := map[string]int64{
 			"FirstName":     FailLenMax,
 			"LastName":      FailLenMin,
 			"Age":           FailValMin,
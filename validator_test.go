@@ -1,8 +1,20 @@
 package structvalidator
 
 import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"log"
+	"math"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+	"unicode"
 )
 
 type Test1 struct {
@@ -44,10 +56,482 @@ type Test4 struct {
 	PrimaryEmail string ``
 }
 
+type Test5 struct {
+	Price     float64 `validation:"valmin:1"`
+	Discount  float32 `validation:"valmin:0 valmax:0.5"`
+	Weight    float64 `validation:"req"`
+	BelowZero float64 `validation:"valmin:-6.5 valmax:-2.25"`
+}
+
+type Test6 struct {
+	Count    uint   `validation:"valmax:10"`
+	Small    uint8  `validation:"valmin:1 valmax:255"`
+	Negative uint16 `validation:"valmin:-5 valmax:5"`
+	Big      uint64 `validation:"valmin:9223372036854775807"`
+	Required uint32 `validation:"req"`
+}
+
+type Test7 struct {
+	TermsAccepted bool `validation:"istrue"`
+	Unsubscribed  bool `validation:"isfalse"`
+	Consent       bool `validation:"req"`
+	Optional      bool ``
+}
+
+type Test8Profile struct {
+	FirstName string `validation:"req lenmin:2"`
+	Age       int    `validation:"valmin:18"`
+}
+
+type Test8 struct {
+	Profile         *Test8Profile `validation:"req"`
+	OptionalProfile *Test8Profile ``
+	Score           *int          `validation:"req valmin:1 valmax:10"`
+	OptionalScore   *int          `validation:"valmax:10"`
+}
+
+type Test9 struct {
+	Country  string `validation:"req len:2"`
+	Barcode  string `validation:"len:13"`
+	Empty    string `validation:"len:0"`
+	Optional string ``
+}
+
+type Test10 struct {
+	Status   string `validation:"oneof:draft,published,archived"`
+	Priority int    `validation:"oneof:1,2,3"`
+	Level    uint8  `validation:"oneof:0,5"`
+}
+
+type Test11 struct {
+	Password        string `validation:"req lenmin:8"`
+	ConfirmPassword string `validation:"req eqfield:Password"`
+	Count           int    ``
+	ConfirmCount    int64  `validation:"eqfield:Count"`
+}
+
+type Test12 struct {
+	Password        string ``
+	ConfirmPassword string `validation:"eqfield:Passwd"`
+}
+
+type Test13 struct {
+	CardNumber string ``
+	CardExpiry string ``
+	CardCVV    string `validation:"required_with:CardNumber,CardExpiry"`
+	Quantity   int    ``
+	Unit       string `validation:"required_with:Quantity"`
+}
+
+type Test14 struct {
+	Tags     []string  `validation:"req lenmin:1 lenmax:3"`
+	Scores   []int     `validation:"lenmax:2"`
+	Position [3]int    `validation:"len:3"`
+	Codes    [2]string `validation:"lenmax:2"`
+}
+
+type Test15 struct {
+	Website     string `validation:"req url"`
+	Callback    string `validation:"url"`
+	HomepageURL string ``
+}
+
+type Test16 struct {
+	ID        string `validation:"uuid"`
+	RequestID string `validation:"uuid:v4"`
+}
+
+type Test17 struct {
+	FirstName    string        `json:"first_name" validation:"req"`
+	PrimaryEmail string        `json:"primary_email,omitempty" validation:"req email"`
+	LastName     string        `json:"-" validation:"req"`
+	Age          int           `json:",omitempty" validation:"valmin:18"`
+	Profile      *Test8Profile `json:"profile"`
+}
+
+type Test18 struct {
+	Username string `validation:"req trim lenmin:3 lenmax:8"`
+	Status   string `validation:"trim oneof:draft,published"`
+	Comment  string `validation:"req"`
+}
+
+type Test19 struct {
+	FirstName string `validation:"alpha"`
+	PIN       string `validation:"numeric len:4"`
+	Username  string `validation:"alphanumeric" validation_regexp:"^[a-z]"`
+}
+
+type Test20 struct {
+	Meta   map[string]string `validation:"req lenmax:2"`
+	Labels map[string]int    `validation:"lenmin:1"`
+}
+
+type Test21 struct {
+	Name    string `validation:"reqtrim lenmax:6"`
+	Comment string `validation:"req"`
+}
+
+type Test22 struct {
+	CreatedAt  time.Time  `validation:"req"`
+	StartsAt   time.Time  `validation:"after:now"`
+	FinishedAt *time.Time `validation:"before:now"`
+	UpdatedAt  time.Time  ``
+}
+
+type Test25 struct {
+	Username string `validation:"regexpnot:^(admin|root)$" validation_regexp:"^[a-z]+$"`
+	Comment  string `validation_regexpnot:"(?i)darn"`
+}
+
+type Test26Base struct {
+	ID   int    `validation:"req"`
+	Name string `validation:"lenmin:10"`
+}
+
+type Test26Named struct {
+	Test26Base
+	Name  string `validation:"req lenmin:2"`
+	Label string `validation:"req"`
+}
+
+type Test26 struct {
+	*Test26Named
+	Email string `validation:"req email"`
+}
+
+type Test27 struct {
+	Mask uint64 `validation:"valmin:9223372036854775808 valmax:18446744073709551615"`
+	Key  uint64 `validation:"valmax:18446744073709551614"`
+}
+
+type Test28 struct {
+	Slug    string `validation:"lowercase lenmax:10"`
+	Country string `validation:"uppercase len:2"`
+}
+
+type Test29 struct {
+	CustomerID string `validation:"req prefix:cus_"`
+	Filename   string `validation:"suffix:.pdf"`
+	Code       string `validation:"prefix:A- suffix:-Z"`
+}
+
+type Test30 struct {
+	Email string `validation:"email"`
+	Phone string ``
+	Fax   string ``
+}
+
+type Test31 struct {
+	Price    string      `validation:"numericrange valmin:0 valmax:1000"`
+	Quantity json.Number `validation:"req numericrange valmin:1"`
+}
+
+type Test32Street struct {
+	Name string `validation:"req"`
+}
+
+type Test32Address struct {
+	ZipCode string `validation:"req len:5"`
+	Street  *Test32Street
+}
+
+type Test32 struct {
+	ZipCode string `validation:"len:6"`
+	Address *Test32Address
+}
+
+type Test33 struct {
+	Handle  string `validation:"contains:@ excludes:admin"`
+	Comment string `validation:"excludes:http:// excludes:https://"`
+	Code    string `validation:"req contains:X"`
+}
+
+type Test34 struct {
+	Name    string    `validation:"req"`
+	secret  string    `validation:"req lenmin:8"`
+	expires time.Time `validation:"req"`
+}
+
+type Test35 struct {
+	StartTs  int64
+	EndTs    int64   `validation:"gtfield:StartTs"`
+	NotAfter int64   `validation:"gtefield:StartTs"`
+	MinPrice float64 `validation:"ltefield:MaxPrice"`
+	MaxPrice uint    `validation:"req"`
+	Discount float32 `validation:"ltfield:MaxPrice"`
+}
+
+type Test35Invalid struct {
+	Name  string
+	Count int `validation:"gtfield:Name"`
+}
+
+type Test36 struct {
+	Email        string `validation:"req email"`
+	WorkEmail    string `validation:"email"`
+	PrimaryEmail string
+}
+
+type Test37 struct {
+	Address    string `validation:"req ip"`
+	AddressV4  string `validation:"ip:v4"`
+	AddressV6  string `validation:"ip:v6"`
+	Network    string `validation:"req cidr"`
+	NetworkOpt string `validation:"cidr"`
+}
+
+type Test38 struct {
+	Name     interface{} `validation:"req lenmin:3"`
+	Port     interface{} `validation:"req valmin:1 valmax:65535"`
+	Optional interface{} `validation:"email"`
+	Stringer fmt.Stringer
+}
+
+type Test39 struct {
+	Pin     int    `validation:"digits:4"`
+	Account uint64 `validation:"digitsmin:6 digitsmax:12"`
+	Offset  int32  `validation:"digitsmax:2"`
+}
+
+type Test40 struct {
+	Status int    `validation:"oneofint:200,404,500"`
+	Offset int8   `validation:"oneofint:-1,0,1"`
+	Role   uint16 `validation:"req oneofint:1,2,3"`
+}
+
+type Test41 struct {
+	Token    string `validation:"req base64"`
+	URLToken string `validation:"base64url"`
+	Checksum string `validation:"hex"`
+}
+
+type Test42 struct {
+	Score   int     `validation:"between:10,20"`
+	Zero    int     `validation:"between:0,0"`
+	Delta   float64 `validation:"between:-1.5,1.5"`
+	Percent uint8   `validation:"req between:0,100"`
+}
+
+type Test43Status string
+
+type Test43Age int
+
+type Test43Tags []string
+
+type Test43 struct {
+	Status  Test43Status `validation:"req lenmin:3 oneof:new,active,closed"`
+	Age     Test43Age    `validation:"req valmin:18"`
+	Tags    Test43Tags   `validation:"lenmax:2"`
+	Minimum Test43Age    `validation:"ltefield:Age"`
+}
+
+type Test44 struct {
+	PostSlug     string `validation:"req"`
+	CategorySlug string
+	PrimaryEmail string
+}
+
+type Test45 struct {
+	Meta   map[string]string      `validation:"lenmax:3" validation_keys:"regexp:^[a-z]+$" validation_values:"req lenmax:5"`
+	Scores map[int]int            `validation_keys:"valmin:1" validation_values:"valmax:100"`
+	Extra  map[string]interface{} `validation_values:"req lenmin:2"`
+}
+
+type Test46 struct {
+	Amount   string `validation:"req decimal:2"`
+	Quantity string `validation:"decimal:0"`
+}
+
+type Test47Item struct {
+	Name     string `validation:"req"`
+	Quantity int    `validation:"valmin:1"`
+}
+
+type Test47 struct {
+	Items    []Test47Item `validation:"req lenmax:3"`
+	Previous []*Test47Item
+	Fixed    [2]Test47Item
+}
+
+type Test48 struct {
+	Name     string  `validation:"req lenmin:3"`
+	Nickname string  `validation:"omitempty lenmin:3 alpha"`
+	Email    string  `validation:"email"`
+	Age      *int    `validation:"valmin:18"`
+	Website  *string `validation:"req url"`
+}
+
+type Test49 struct {
+	Payload  string `validation:"req json"`
+	Metadata string `validation:"json"`
+}
+
+type Test50 struct {
+	Positive    int     `validation:"positive"`
+	NonNegative int64   `validation:"nonneg"`
+	Negative    float64 `validation:"negative"`
+	NonPositive int8    `validation:"nonpositive"`
+}
+
+type Test51 struct {
+	Bytes string `validation:"lenmax:4"`
+	Runes string `validation:"runelen lenmin:2 lenmax:4"`
+	Code  string `validation:"runelen len:3"`
+}
+
+type Test52 struct {
+	Email   string
+	Phone   string `validation:"required_without:Email lenmin:6"`
+	Fax     int    `validation:"required_without:Email,Phone"`
+	Address string `validation:"required_without:Email"`
+}
+
+type Test53 struct {
+	Events   chan string `validation:"req"`
+	Handler  func()      `validation:"req"`
+	Optional chan int
+}
+
+type Test54 struct {
+	BirthDate string `validation:"req datetime:2006-01-02"`
+	CreatedAt string `validation_datetime:"2006-01-02 15:04:05"`
+	Expiry    string `validation:"datetime:01/06"`
+}
+
+type Test55Base struct {
+	ID   int    `validation:"req"`
+	Name string `validation:"req lenmin:3"`
+}
+
+type Test55Audit struct {
+	CreatedBy string `validation:"req"`
+}
+
+type Test55 struct {
+	*Test55Base `validation:"req"`
+	*Test55Audit
+	Title string `validation:"req"`
+}
+
+type Test56 struct {
+	Score    int    `validation:"req"`
+	Rank     int    `validation:"valmin:1"`
+	Country  string `validation:"req lenmin:2"`
+	Nickname string `validation:"omitempty lenmin:3"`
+}
+
+type Test57 struct {
+	StartTs   int64     `validation:"req"`
+	EndTs     int64     `validation:"req gtefield:StartTs withinfield:StartTs,3600"`
+	Target    float64   `validation:"req"`
+	Measured  float64   `validation:"withinfield:Target,0.5"`
+	StartTime time.Time `validation:"req"`
+	EndTime   time.Time `validation:"withinfield:StartTime,60"`
+}
+
+type Test58 struct {
+	CardNumber string `validation:"req creditcard"`
+	BackupCard string `validation:"creditcard"`
+}
+
+type Test59 struct {
+	Hostname string `validation:"req hostname"`
+	Domain   string `validation:"req fqdn"`
+	DBHost   string
+}
+
+type Test60 struct {
+	Name     sql.NullString  `validation:"req lenmin:3"`
+	Age      sql.NullInt64   `validation:"req valmin:0 valmax:150"`
+	Score    sql.NullFloat64 `validation:"valmax:10"`
+	Nickname sql.NullString  `validation:"lenmin:3"`
+	Level    sql.Null[int]   `validation:"valmin:1"`
+}
+
+type Test61 struct {
+	Status   string `validation:"req oneofci:draft,published"`
+	Category string `validation:"oneof:news,blog"`
+}
+
+type Test62 struct {
+	Quantity int     `validation:"req multipleof:12"`
+	Offset   int64   `validation:"multipleof:-5"`
+	Packs    uint    `validation:"multipleof:6"`
+	Weight   float64 `validation:"multipleof:0.1"`
+}
+
+type Test63 struct {
+	Status      int    `validation:"req ranges:200-299,300-399"`
+	Temperature int64  `validation:"ranges:-30--10,10-30"`
+	Port        uint16 `validation:"ranges:80-80,8000-8999"`
+}
+
+type Test64 struct {
+	Token    string `validation:"req charset:abcdef0123456789"`
+	Comment  string `validation:"charsetnot:<>&"`
+	Nickname string `validation:"charset:ąęźż_ charsetnot:_"`
+}
+
+type Test65 struct {
+	Codes   []string  `validation:"req lenmax:3" validation_elem:"req lenmax:5 uppercase"`
+	Scores  [3]int    `validation_elem:"valmin:0 valmax:100"`
+	Aliases []*string `validation_elem:"req lenmin:2"`
+}
+
+type Test66 struct {
+	Amount   complex128 `validation:"req"`
+	Settings struct {
+		Theme string
+	}
+	Comment string
+}
+
+type Test67 struct {
+	Count    int     `validation:"gt:5 lt:10"`
+	Quantity uint    `validation:"gte:5 lte:10"`
+	Ratio    float64 `validation:"gt:0 lte:1"`
+	Balance  int     `validation:"req gte:0"`
+}
+
+type Test68 struct {
+	Namespace string `validation:"req lenmax:10"`
+	Name      *string
+	Count     int
+}
+
+type Test69 struct {
+	Version    string `validation:"req semver"`
+	APIVersion string `validation:"omitempty semver:v"`
+}
+
+type Test70 struct {
+	Discount int     `validation:"percent"`
+	Progress float64 `validation:"req percentfloat"`
+	Share    uint8   `validation:"percent"`
+}
+
+type Test71Plugin interface {
+	PluginName() string
+}
+
+type Test71Impl struct {
+	Name    string `validation:"req lenmin:3"`
+	Version string `validation:"req semver"`
+}
+
+func (p Test71Impl) PluginName() string {
+	return p.Name
+}
+
+type Test71 struct {
+	Test71Plugin `validation:"req"`
+	ID           string `validation:"req"`
+}
+
 func TestWithDefaultValues(t *testing.T) {
 	s := Test1{}
 	expectedBool := false
-	expectedFailedFields := map[string]int{
+	expectedFailedFields := map[string]int64{
 		"FirstName": FailEmpty,
 		"LastName":  FailEmpty,
 		"Age":       FailValMin,
@@ -60,6 +544,21 @@ func TestWithDefaultValues(t *testing.T) {
 	compare(&s, expectedBool, expectedFailedFields, opts, t)
 }
 
+func TestWithDefaultValuesAndNilOptions(t *testing.T) {
+	s := Test1{}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"FirstName": FailEmpty,
+		"LastName":  FailEmpty,
+		"Age":       FailValMin,
+		"PostCode":  FailEmpty,
+		"Email":     FailEmpty,
+		"Country":   FailRegexp,
+		"BelowZero": FailValMax,
+	}
+	compare(&s, expectedBool, expectedFailedFields, nil, t)
+}
+
 func TestWithInvalidValues(t *testing.T) {
 	s := Test1{
 		FirstName:     "123456789012345678901234567890",
@@ -74,7 +573,7 @@ func TestWithInvalidValues(t *testing.T) {
 		County:        "",
 	}
 	expectedBool := false
-	expectedFailedFields := map[string]int{
+	expectedFailedFields := map[string]int64{
 		"FirstName":     FailLenMax,
 		"LastName":      FailLenMin,
 		"Age":           FailValMin,
@@ -102,7 +601,7 @@ func TestWithValidValues(t *testing.T) {
 		County:        "Enfield",
 	}
 	expectedBool := true
-	expectedFailedFields := map[string]int{}
+	expectedFailedFields := map[string]int64{}
 	opts := &ValidationOptions{}
 	compare(&s, expectedBool, expectedFailedFields, opts, t)
 }
@@ -121,7 +620,7 @@ func TestWithInvalidValuesAndFieldRestriction(t *testing.T) {
 		County:        "",
 	}
 	expectedBool := false
-	expectedFailedFields := map[string]int{
+	expectedFailedFields := map[string]int64{
 		"FirstName": FailLenMax,
 		"LastName":  FailLenMin,
 	}
@@ -134,6 +633,60 @@ func TestWithInvalidValuesAndFieldRestriction(t *testing.T) {
 	compare(&s, expectedBool, expectedFailedFields, opts, t)
 }
 
+func TestWithInvalidValuesAndSkippedFields(t *testing.T) {
+	s := Test1{
+		FirstName:     "123456789012345678901234567890",
+		LastName:      "b",
+		Age:           15,
+		Price:         0,
+		PostCode:      "AA123",
+		Email:         "invalidEmail",
+		BelowZero:     8,
+		DiscountPrice: 9999,
+		Country:       "Tokelau",
+		County:        "",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"FirstName":     FailLenMax,
+		"LastName":      FailLenMin,
+		"Age":           FailValMin,
+		"BelowZero":     FailValMax,
+		"DiscountPrice": FailValMax,
+	}
+	opts := &ValidationOptions{
+		SkipFields: map[string]bool{
+			"PostCode": true,
+			"Email":    true,
+			"Country":  true,
+		},
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestWithInvalidValuesAndFieldRestrictionAndSkippedFields(t *testing.T) {
+	s := Test1{
+		FirstName: "123456789012345678901234567890",
+		LastName:  "b",
+		Age:       15,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"LastName": FailLenMin,
+	}
+	opts := &ValidationOptions{
+		RestrictFields: map[string]bool{
+			"FirstName": true,
+			"LastName":  true,
+		},
+		SkipFields: map[string]bool{
+			"FirstName": true,
+			"Age":       true,
+		},
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
 func TestWithInvalidValuesAndFieldRestrictionAndOverwrittenFieldTags(t *testing.T) {
 	s := Test1{
 		FirstName:     "123456789012345678901234567890",
@@ -148,7 +701,7 @@ func TestWithInvalidValuesAndFieldRestrictionAndOverwrittenFieldTags(t *testing.
 		County:        "",
 	}
 	expectedBool := false
-	expectedFailedFields := map[string]int{
+	expectedFailedFields := map[string]int64{
 		"LastName": FailLenMin,
 	}
 	opts := &ValidationOptions{
@@ -179,7 +732,7 @@ func TestWithInvalidValuesAndOverwrittenTagName(t *testing.T) {
 		County:        "",
 	}
 	expectedBool := false
-	expectedFailedFields := map[string]int{
+	expectedFailedFields := map[string]int64{
 		"FirstName":     FailLenMax,
 		"LastName":      FailLenMin,
 		"Age":           FailValMin,
@@ -198,7 +751,7 @@ func TestWithInvalidValuesAndOverwrittenTagName(t *testing.T) {
 func TestValMinMaxWithDefault(t *testing.T) {
 	s := Test3{}
 	expectedBool := false
-	expectedFailedFields := map[string]int{
+	expectedFailedFields := map[string]int64{
 		"NotZero": FailValMin,
 		"OnlyMin": FailValMin,
 	}
@@ -215,7 +768,7 @@ func TestValMinMaxWithValid(t *testing.T) {
 		OnlyMax: 7,
 	}
 	expectedBool := true
-	expectedFailedFields := map[string]int{}
+	expectedFailedFields := map[string]int64{}
 	opts := &ValidationOptions{
 		OverwriteTagName: "mytag",
 	}
@@ -232,7 +785,7 @@ func TestValMinMaxWithInvalid(t *testing.T) {
 		OnlyMax:  -6,
 	}
 	expectedBool := false
-	expectedFailedFields := map[string]int{
+	expectedFailedFields := map[string]int64{
 		"ZeroMin":  FailValMin,
 		"ZeroBoth": FailValMin,
 		"NotZero":  FailValMin,
@@ -249,7 +802,7 @@ func TestWithInvalidValuesWithSuffixValidation(t *testing.T) {
 		PrimaryEmail: "invalidemail",
 	}
 	expectedBool := false
-	expectedFailedFields := map[string]int{
+	expectedFailedFields := map[string]int64{
 		"PrimaryEmail": FailEmail,
 	}
 	opts := &ValidationOptions{
@@ -263,7 +816,7 @@ func TestWithInvalidValuesWithoutSuffixValidation(t *testing.T) {
 		PrimaryEmail: "invalidemail",
 	}
 	expectedBool := true
-	expectedFailedFields := map[string]int{}
+	expectedFailedFields := map[string]int64{}
 	opts := &ValidationOptions{
 		ValidateWhenSuffix: false,
 	}
@@ -284,7 +837,7 @@ func TestWithOverwrittenValues(t *testing.T) {
 		County:        "",
 	}
 	expectedBool := false
-	expectedFailedFields := map[string]int{
+	expectedFailedFields := map[string]int64{
 		"Age": FailValMax,
 	}
 	opts := &ValidationOptions{
@@ -302,15 +855,3812 @@ func TestWithOverwrittenValues(t *testing.T) {
 	compare(&s, expectedBool, expectedFailedFields, opts, t)
 }
 
-func compare(s interface{}, expectedBool bool, expectedFailedFields map[string]int, options *ValidationOptions, t *testing.T) {
-	valid, failedFields := Validate(s, options)
+func TestWithOverwrittenValuesOfConvertibleTypes(t *testing.T) {
+	s := Test6{Count: 1, Small: 1, Big: math.MaxUint64, Required: 1}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Count": FailValMax,
+	}
+	opts := &ValidationOptions{
+		OverwriteFieldValues: map[string]interface{}{
+			"Count": uint64(11),
+			"Small": uint(200),
+		},
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestWithOverwrittenValuesOfMismatchedTypes(t *testing.T) {
+	s := Test1{}
+	overwriteFieldValues := []map[string]interface{}{
+		{"Age": "20"},
+		{"FirstName": 123456},
+		{"Age": 20.5},
+		{"FirstName": nil},
+	}
+	for _, overwrite := range overwriteFieldValues {
+		opts := &ValidationOptions{
+			OverwriteFieldValues: overwrite,
+		}
+		_, _, err := ValidateE(&s, opts)
+		if err == nil {
+			t.Errorf("ValidateE did not return an error for OverwriteFieldValues %v", overwrite)
+		}
+	}
+
+	// value that does not fit in the field type
+	s6 := Test6{}
+	opts := &ValidationOptions{
+		OverwriteFieldValues: map[string]interface{}{
+			"Small": uint(256),
+		},
+	}
+	_, _, err := ValidateE(&s6, opts)
+	if err == nil {
+		t.Errorf("ValidateE did not return an error for overwrite value that overflows the field")
+	}
+}
+
+func TestWithOverwrittenNilValues(t *testing.T) {
+	name := "John"
+	s := struct {
+		Name *string           `validation:"req"`
+		Tags []string          `validation:"req"`
+		Meta map[string]string `validation:"lenmax:2"`
+	}{
+		Name: &name,
+		Tags: []string{"a"},
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Name": FailNil,
+		"Tags": FailEmpty,
+	}
+	opts := &ValidationOptions{
+		OverwriteFieldValues: map[string]interface{}{
+			"Name": nil,
+			"Tags": nil,
+			"Meta": nil,
+		},
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestWithRestrictedFieldIndexes(t *testing.T) {
+	s := Test1{}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"FirstName": FailEmpty,
+		"Age":       FailValMin,
+		"Email":     FailEmpty,
+	}
+	opts := &ValidationOptions{
+		RestrictFields: map[string]bool{
+			"Email": true,
+		},
+		RestrictFieldIndexes: map[int]bool{
+			0: true,
+			2: true,
+			3: true,
+		},
+		SkipFields: map[string]bool{
+			"Price": true,
+		},
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestFloatWithDefaultValues(t *testing.T) {
+	s := Test5{}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Price":     FailValMin,
+		"Weight":    FailZero,
+		"BelowZero": FailValMax,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestFloatWithInvalidValues(t *testing.T) {
+	s := Test5{
+		Price:     0.99,
+		Discount:  0.75,
+		Weight:    1.5,
+		BelowZero: -6.75,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Price":     FailValMin,
+		"Discount":  FailValMax,
+		"BelowZero": FailValMin,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestFloatWithValidValues(t *testing.T) {
+	s := Test5{
+		Price:     1,
+		Discount:  0.5,
+		Weight:    0.01,
+		BelowZero: -2.25,
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestUintWithDefaultValues(t *testing.T) {
+	s := Test6{}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Small":    FailValMin,
+		"Big":      FailValMin,
+		"Required": FailZero,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestUintWithInvalidValues(t *testing.T) {
+	s := Test6{
+		Count:    11,
+		Small:    0,
+		Negative: 6,
+		Big:      math.MaxInt64 - 1,
+		Required: 1,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Count":    FailValMax,
+		"Small":    FailValMin,
+		"Negative": FailValMax,
+		"Big":      FailValMin,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestUintWithValidValues(t *testing.T) {
+	s := Test6{
+		Count:    10,
+		Small:    math.MaxUint8,
+		Negative: 0,
+		Big:      math.MaxUint64,
+		Required: 1,
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestBoolWithDefaultValues(t *testing.T) {
+	s := Test7{}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"TermsAccepted": FailBool,
+		"Consent":       FailBool,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestBoolWithInvalidValues(t *testing.T) {
+	s := Test7{
+		TermsAccepted: false,
+		Unsubscribed:  true,
+		Consent:       false,
+		Optional:      true,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"TermsAccepted": FailBool,
+		"Unsubscribed":  FailBool,
+		"Consent":       FailBool,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestBoolWithValidValues(t *testing.T) {
+	s := Test7{
+		TermsAccepted: true,
+		Unsubscribed:  false,
+		Consent:       true,
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestPointerWithNilValues(t *testing.T) {
+	s := Test8{}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Profile": FailNil,
+		"Score":   FailNil,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestPointerWithInvalidValues(t *testing.T) {
+	score := 11
+	optionalScore := 12
+	s := Test8{
+		Profile:         &Test8Profile{FirstName: "J", Age: 20},
+		OptionalProfile: &Test8Profile{FirstName: "John", Age: 15},
+		Score:           &score,
+		OptionalScore:   &optionalScore,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Profile.FirstName":   FailLenMin,
+		"OptionalProfile.Age": FailValMin,
+		"Score":               FailValMax,
+		"OptionalScore":       FailValMax,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestPointerWithValidValues(t *testing.T) {
+	score := 10
+	s := Test8{
+		Profile: &Test8Profile{FirstName: "John", Age: 20},
+		Score:   &score,
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestWithMultipleFailuresOnField(t *testing.T) {
+	s := Test1{
+		FirstName:     "Johnny",
+		LastName:      "Smith",
+		Age:           35,
+		PostCode:      "A",
+		Email:         "john@example.com",
+		BelowZero:     -4,
+		DiscountPrice: 8000,
+		Country:       "GB",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"PostCode": FailRegexp,
+		"Email":    FailLenMax | FailEmail,
+	}
+	opts := &ValidationOptions{
+		OverwriteFieldTags: map[string]map[string]string{
+			"Email": map[string]string{
+				"validation": "req email lenmax:3",
+			},
+		},
+		OverwriteFieldValues: map[string]interface{}{
+			"Email": "invalidEmail",
+		},
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestLenWithDefaultValues(t *testing.T) {
+	s := Test9{}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Country": FailEmpty,
+		"Barcode": FailLen,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestLenWithInvalidValues(t *testing.T) {
+	s := Test9{
+		Country: "GBR",
+		Barcode: "123456789012",
+		Empty:   "x",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Country": FailLen,
+		"Barcode": FailLen,
+		"Empty":   FailLen,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestLenWithValidValues(t *testing.T) {
+	s := Test9{
+		Country: "GB",
+		Barcode: "1234567890123",
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestOneOfWithDefaultValues(t *testing.T) {
+	s := Test10{}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Status":   FailOneOf,
+		"Priority": FailOneOf,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestOneOfWithInvalidValues(t *testing.T) {
+	s := Test10{
+		Status:   "Draft",
+		Priority: 4,
+		Level:    4,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Status":   FailOneOf,
+		"Priority": FailOneOf,
+		"Level":    FailOneOf,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestOneOfWithValidValues(t *testing.T) {
+	s := Test10{
+		Status:   "published",
+		Priority: 3,
+		Level:    5,
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestValidateEWithInvalidRegexp(t *testing.T) {
+	s := Test1{}
+	opts := &ValidationOptions{
+		OverwriteFieldTags: map[string]map[string]string{
+			"PostCode": map[string]string{
+				"validation_regexp": "(",
+			},
+		},
+	}
+	valid, failedFields, err := ValidateE(&s, opts)
+	if err == nil {
+		t.Fatalf("ValidateE did not return an error for invalid regexp")
+	}
+	if valid || failedFields != nil {
+		t.Fatalf("ValidateE returned invalid values along with an error")
+	}
+	if !strings.Contains(err.Error(), "PostCode") || !strings.Contains(err.Error(), "'('") {
+		t.Fatalf("ValidateE returned error without field name and pattern: %s", err.Error())
+	}
+}
+
+func TestValidateEWithValidValues(t *testing.T) {
+	s := Test4{
+		PrimaryEmail: "john@example.com",
+	}
+	valid, failedFields, err := ValidateE(&s, &ValidationOptions{ValidateWhenSuffix: true})
+	if err != nil {
+		t.Fatalf("ValidateE returned an error: %s", err.Error())
+	}
+	if !valid || len(failedFields) != 0 {
+		t.Fatalf("ValidateE returned invalid boolean value")
+	}
+}
+
+func TestEqFieldWithInvalidValues(t *testing.T) {
+	s := Test11{
+		Password:        "password123",
+		ConfirmPassword: "password124",
+		Count:           3,
+		ConfirmCount:    4,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"ConfirmPassword": FailEqField,
+		"ConfirmCount":    FailEqField,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestEqFieldWithEmptyValue(t *testing.T) {
+	s := Test11{
+		Password: "password123",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"ConfirmPassword": FailEmpty,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestEqFieldWithValidValues(t *testing.T) {
+	s := Test11{
+		Password:        "password123",
+		ConfirmPassword: "password123",
+		Count:           3,
+		ConfirmCount:    3,
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestEqFieldWithMissingField(t *testing.T) {
+	s := Test12{}
+	_, _, err := ValidateE(&s, &ValidationOptions{})
+	if err == nil || !strings.Contains(err.Error(), "Passwd") {
+		t.Fatalf("ValidateE did not return an error for non-existing field in eqfield")
+	}
+}
+
+func TestRequiredWithWithDefaultValues(t *testing.T) {
+	s := Test13{}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestRequiredWithWithInvalidValues(t *testing.T) {
+	s := Test13{
+		CardExpiry: "12/30",
+		Quantity:   5,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"CardCVV": FailEmpty,
+		"Unit":    FailEmpty,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestRequiredWithWithValidValues(t *testing.T) {
+	s := Test13{
+		CardNumber: "4111111111111111",
+		CardCVV:    "123",
+		Quantity:   5,
+		Unit:       "kg",
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestRequiredWithoutWithNeitherPresent(t *testing.T) {
+	s := Test52{}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Phone":   FailEmpty,
+		"Fax":     FailZero,
+		"Address": FailEmpty,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestRequiredWithoutWithOnePresent(t *testing.T) {
+	s := Test52{
+		Phone: "123456",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Address": FailEmpty,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestRequiredWithoutWithBothPresent(t *testing.T) {
+	s := Test52{
+		Email: "john@example.com",
+		Phone: "123",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Phone": FailLenMin,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s.Phone = "123456"
+	expectedBool = true
+	expectedFailedFields = map[string]int64{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestSliceWithNilValues(t *testing.T) {
+	s := Test14{}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Tags": FailEmpty,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestSliceWithInvalidValues(t *testing.T) {
+	s := Test14{
+		Tags:   []string{"a", "b", "c", "d"},
+		Scores: []int{1, 2, 3},
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Tags":   FailLenMax,
+		"Scores": FailLenMax,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestSliceWithEmptyValues(t *testing.T) {
+	s := Test14{
+		Tags:   []string{},
+		Scores: []int{},
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Tags": FailEmpty,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestSliceWithValidValues(t *testing.T) {
+	s := Test14{
+		Tags:   []string{"a", "b", "c"},
+		Scores: []int{1, 2},
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestURLWithInvalidValues(t *testing.T) {
+	s := Test15{
+		Website:     "example.com",
+		Callback:    "/path/only",
+		HomepageURL: "www.example.com/home",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Website":     FailURL,
+		"Callback":    FailURL,
+		"HomepageURL": FailURL,
+	}
+	opts := &ValidationOptions{
+		ValidateWhenSuffix: true,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestURLWithValidValues(t *testing.T) {
+	s := Test15{
+		Website:     "https://example.com/path?q=1",
+		Callback:    "http://localhost:8080/callback",
+		HomepageURL: "https://example.com",
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{
+		ValidateWhenSuffix: true,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestUUIDWithNilUUID(t *testing.T) {
+	s := Test16{
+		ID:        "00000000-0000-0000-0000-000000000000",
+		RequestID: "00000000-0000-0000-0000-000000000000",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"RequestID": FailUUID,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestUUIDWithInvalidValues(t *testing.T) {
+	s := Test16{
+		ID:        "123e4567-e89b-12d3-a456-42661417400",
+		RequestID: "123e4567-e89b-12d3-a456-426614174000",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"ID":        FailUUID,
+		"RequestID": FailUUID,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestUUIDWithValidValues(t *testing.T) {
+	s := Test16{
+		ID:        "123E4567-E89B-12D3-A456-426614174000",
+		RequestID: "f47ac10b-58cc-4372-a567-0e02b2c3d479",
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestWithTagNameInErrors(t *testing.T) {
+	s := Test17{
+		PrimaryEmail: "invalidEmail",
+		Profile:      &Test8Profile{},
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"first_name":        FailEmpty,
+		"primary_email":     FailEmail,
+		"LastName":          FailEmpty,
+		"Age":               FailValMin,
+		"profile.FirstName": FailEmpty,
+		"profile.Age":       FailValMin,
+	}
+	opts := &ValidationOptions{
+		UseTagNameInErrors: "json",
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestTrimWithInvalidValues(t *testing.T) {
+	s := Test18{
+		Username: "   ",
+		Status:   " archived ",
+		Comment:  "   ",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Username": FailEmpty,
+		"Status":   FailOneOf,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestTrimWithTooShortValue(t *testing.T) {
+	s := Test18{
+		Username: "  jo    ",
+		Status:   "draft",
+		Comment:  "x",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Username": FailLenMin,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+	if s.Username != "  jo    " {
+		t.Fatalf("Validate modified the value of trimmed field")
+	}
+}
+
+func TestTrimWithValidValues(t *testing.T) {
+	s := Test18{
+		Username: "  johnny    ",
+		Status:   " published\n",
+		Comment:  "x",
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestCharactersWithInvalidValues(t *testing.T) {
+	s := Test19{
+		FirstName: "Zoë",
+		PIN:       "12a",
+		Username:  "1john_doe",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"FirstName": FailAlpha,
+		"PIN":       FailNumeric | FailLen,
+		"Username":  FailAlphanumeric | FailRegexp,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestCharactersWithValidValues(t *testing.T) {
+	s := Test19{
+		FirstName: "Zoe",
+		PIN:       "0123",
+		Username:  "john1",
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestMapWithNilValues(t *testing.T) {
+	s := Test20{}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Meta":   FailEmpty,
+		"Labels": FailLenMin,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestMapWithInvalidValues(t *testing.T) {
+	s := Test20{
+		Meta:   map[string]string{"a": "1", "b": "2", "c": "3"},
+		Labels: map[string]int{},
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Meta":   FailLenMax,
+		"Labels": FailLenMin,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestMapWithValidValues(t *testing.T) {
+	s := Test20{
+		Meta:   map[string]string{"a": "1", "b": "2"},
+		Labels: map[string]int{"x": 1},
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestReqTrimWithInvalidValues(t *testing.T) {
+	s := Test21{
+		Name:    " \t\u00a0\u3000\n",
+		Comment: " \t",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Name": FailEmpty,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestReqTrimWithPresentValue(t *testing.T) {
+	s := Test21{
+		Name:    "\t John ",
+		Comment: "x",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Name": FailLenMax,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestNotBlank(t *testing.T) {
+	opts := &ValidationOptions{}
+	for _, value := range []string{"", "   ", "\t\n"} {
+		s := struct {
+			Name string `validation:"notblank"`
+		}{Name: value}
+		expectedBool := false
+		expectedFailedFields := map[string]int64{
+			"Name": FailEmpty,
+		}
+		compare(&s, expectedBool, expectedFailedFields, opts, t)
+	}
+
+	s := struct {
+		Name string `validation:"notblank"`
+	}{Name: " John "}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestTimeWithDefaultValues(t *testing.T) {
+	s := Test22{}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"CreatedAt": FailTime,
+		"StartsAt":  FailTime,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestTimeWithInvalidValues(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	s := Test22{
+		CreatedAt:  time.Now(),
+		StartsAt:   time.Now().Add(-time.Hour),
+		FinishedAt: &future,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"StartsAt":   FailTime,
+		"FinishedAt": FailTime,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestTimeWithValidValues(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	s := Test22{
+		CreatedAt:  time.Now(),
+		StartsAt:   time.Now().Add(time.Hour),
+		FinishedAt: &past,
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestRegexpNotWithInvalidValues(t *testing.T) {
+	s := Test25{
+		Username: "admin",
+		Comment:  "Darn it",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Username": FailRegexpNot,
+		"Comment":  FailRegexpNot,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s = Test25{
+		Username: "Admin",
+		Comment:  "Fine",
+	}
+	expectedFailedFields = map[string]int64{
+		"Username": FailRegexp,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestRegexpNotWithValidValues(t *testing.T) {
+	s := Test25{
+		Username: "administrator",
+		Comment:  "Fine",
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestEmbeddedWithDefaultValues(t *testing.T) {
+	s := Test26{
+		Test26Named: &Test26Named{},
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"ID":    FailZero,
+		"Name":  FailEmpty,
+		"Label": FailEmpty,
+		"Email": FailEmpty,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestEmbeddedWithShadowedField(t *testing.T) {
+	s := Test26{
+		Test26Named: &Test26Named{
+			Test26Base: Test26Base{ID: 1, Name: "x"},
+			Name:       "Johnny",
+			Label:      "x",
+		},
+		Email: "john@example.com",
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestEmbeddedWithNilPointerAndRestrictedFields(t *testing.T) {
+	s := Test26{}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Email": FailEmpty,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s = Test26{
+		Test26Named: &Test26Named{},
+	}
+	expectedFailedFields = map[string]int64{
+		"ID": FailZero,
+	}
+	opts = &ValidationOptions{
+		RestrictFields: map[string]bool{
+			"ID": true,
+		},
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestUintWithLargeBounds(t *testing.T) {
+	s := Test27{
+		Mask: math.MaxInt64,
+		Key:  math.MaxUint64,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Mask": FailValMin,
+		"Key":  FailValMax,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s = Test27{
+		Mask: math.MaxUint64,
+		Key:  math.MaxUint64 - 1,
+	}
+	compare(&s, true, map[string]int64{}, opts, t)
+}
+
+func TestCaseWithInvalidValues(t *testing.T) {
+	s := Test28{
+		Slug:    "My-Long-Slug",
+		Country: "Gb",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Slug":    FailLowercase | FailLenMax,
+		"Country": FailUppercase,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestCaseWithValidValues(t *testing.T) {
+	s := Test28{
+		Slug:    "my-slug-1",
+		Country: "12",
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestPrefixSuffixWithDefaultValues(t *testing.T) {
+	s := Test29{}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"CustomerID": FailEmpty,
+		"Filename":   FailSuffix,
+		"Code":       FailPrefix | FailSuffix,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestPrefixSuffixWithInvalidValues(t *testing.T) {
+	s := Test29{
+		CustomerID: "usr_123",
+		Filename:   "document.pdf.exe",
+		Code:       "A-123",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"CustomerID": FailPrefix,
+		"Filename":   FailSuffix,
+		"Code":       FailSuffix,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestPrefixSuffixWithValidValues(t *testing.T) {
+	s := Test29{
+		CustomerID: "cus_123",
+		Filename:   "document.pdf",
+		Code:       "A-123-Z",
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestValidateMany(t *testing.T) {
+	values := []Test28{
+		{Slug: "valid", Country: "GB"},
+		{Slug: "Invalid", Country: "GB"},
+		{Slug: "valid", Country: "G"},
+	}
+	pointers := []*Test28{&values[1], nil, &values[0], &values[2]}
+
+	valid, invalidElements := ValidateMany(values, nil)
+	if valid {
+		t.Fatalf("ValidateMany returned invalid boolean value")
+	}
+	if len(invalidElements) != 2 || invalidElements[1]["Slug"] != FailLowercase || invalidElements[2]["Country"] != FailLen {
+		t.Fatalf("ValidateMany returned invalid failed elements %v", invalidElements)
+	}
+
+	valid, invalidElements = ValidateMany(pointers, nil)
+	if valid {
+		t.Fatalf("ValidateMany returned invalid boolean value")
+	}
+	if len(invalidElements) != 2 || invalidElements[0]["Slug"] != FailLowercase || invalidElements[3]["Country"] != FailLen {
+		t.Fatalf("ValidateMany returned invalid failed elements %v", invalidElements)
+	}
+
+	valid, invalidElements = ValidateMany(values[:1], nil)
+	if !valid || len(invalidElements) != 0 {
+		t.Fatalf("ValidateMany returned invalid boolean value")
+	}
+}
+
+func TestSetFieldsCount(t *testing.T) {
+	opts := &ValidationOptions{
+		RestrictFields: map[string]bool{
+			"Phone": true,
+		},
+		SetFieldsCount: []SetFieldsCount{
+			{Fields: []string{"Email", "Phone", "Fax"}, Min: 1},
+			{Fields: []string{"Phone", "Fax"}, Max: 1},
+		},
+	}
+
+	s := Test30{}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		StructKey: FailFieldsMin,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s = Test30{
+		Phone: "123",
+		Fax:   "456",
+	}
+	expectedFailedFields = map[string]int64{
+		StructKey: FailFieldsMax,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s = Test30{
+		Email: "john@example.com",
+		Phone: "123",
+	}
+	compare(&s, true, map[string]int64{}, opts, t)
+}
+
+func TestNumericRangeWithInvalidValues(t *testing.T) {
+	s := Test31{
+		Price:    "twelve",
+		Quantity: json.Number("0.5"),
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Price":    FailNumber,
+		"Quantity": FailValMin,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s = Test31{
+		Price:    "1000.01",
+		Quantity: json.Number("abc"),
+	}
+	expectedFailedFields = map[string]int64{
+		"Price":    FailValMax,
+		"Quantity": FailNumber,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	// NaN and infinity are parsed by strconv but they are not valid numbers
+	for _, value := range []string{"NaN", "nan", "Inf", "+Inf", "-Inf", "infinity"} {
+		s = Test31{
+			Price:    value,
+			Quantity: json.Number(value),
+		}
+		expectedFailedFields = map[string]int64{
+			"Price":    FailNumber,
+			"Quantity": FailNumber,
+		}
+		compare(&s, expectedBool, expectedFailedFields, opts, t)
+	}
+}
+
+func TestNumericRangeWithValidValues(t *testing.T) {
+	s := Test31{
+		Price:    "0",
+		Quantity: json.Number("1e3"),
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestWithOverwrittenNestedValues(t *testing.T) {
+	s := Test32{
+		ZipCode: "123456",
+		Address: &Test32Address{
+			ZipCode: "12345",
+			Street:  &Test32Street{Name: "Main"},
+		},
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"ZipCode":             FailLen,
+		"Address.ZipCode":     FailLen,
+		"Address.Street.Name": FailEmpty,
+	}
+	opts := &ValidationOptions{
+		OverwriteFieldValues: map[string]interface{}{
+			"ZipCode":             "12345",
+			"Address.ZipCode":     "123456",
+			"Address.Street.Name": "",
+		},
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestValidateField(t *testing.T) {
+	cases := []struct {
+		value         interface{}
+		tag           string
+		tagRegexp     string
+		expectedBool  bool
+		expectedFlags int64
+	}{
+		{"", "req lenmin:3", "", false, FailEmpty},
+		{"ab", "req lenmin:3", "", false, FailLenMin},
+		{"abc", "req lenmin:3 lenmax:5", "", true, 0},
+		{"abcdef", "lenmax:5 email", "", false, FailLenMax | FailEmail},
+		{"AB-12", "len:5", "^[A-Z]{2}-[0-9]{2}$", true, 0},
+		{"ab-12", "len:5", "^[A-Z]{2}-[0-9]{2}$", false, FailRegexp},
+		{0, "req", "", false, FailZero},
+		{0, "req valmin:0", "", true, 0},
+		{17, "valmin:18 valmax:150", "", false, FailValMin},
+		{uint8(200), "valmax:150", "", false, FailValMax},
+		{nil, "req", "", false, FailNil},
+		{nil, "", "", true, 0},
+	}
+	for _, c := range cases {
+		valid, failureFlags := ValidateField(c.value, c.tag, c.tagRegexp)
+		if valid != c.expectedBool || failureFlags != c.expectedFlags {
+			t.Fatalf("ValidateField returned %v and %d where it should be %v and %d for %v with '%s'", valid, failureFlags, c.expectedBool, c.expectedFlags, c.value, c.tag)
+		}
+	}
+}
+
+func TestRequiredPrecedence(t *testing.T) {
+	cases := []struct {
+		value         interface{}
+		tag           string
+		expectedFlags int64
+	}{
+		{"", "req lenmin:3", FailEmpty},
+		{"", "req lenmin:3 email alphanumeric", FailEmpty},
+		{"   ", "reqtrim lenmin:5 regexp:^[a-z]+$", FailEmpty},
+		{"ab", "req lenmin:3", FailLenMin},
+		{"a-", "req lenmin:3 alphanumeric", FailLenMin | FailAlphanumeric},
+		{"abc", "req lenmin:3", 0},
+		{0, "req valmin:5", FailValMin},
+		{0, "req multipleof:5 oneofint:5,10", FailZero},
+	}
+	for _, c := range cases {
+		_, failureFlags := ValidateField(c.value, c.tag, "")
+		if failureFlags != c.expectedFlags {
+			t.Fatalf("ValidateField returned %d where it should be %d for %v with '%s'", failureFlags, c.expectedFlags, c.value, c.tag)
+		}
+	}
+
+	s := Test1{}
+	_, failedFields := Validate(&s, nil)
+	if failedFields["FirstName"] != FailEmpty {
+		t.Errorf("Validate returned %v where FirstName should fail with FailEmpty only", failedFields)
+	}
+	s.FirstName = "Jo"
+	_, failedFields = Validate(&s, nil)
+	if failedFields["FirstName"] != FailLenMin {
+		t.Errorf("Validate returned %v where FirstName should fail with FailLenMin only", failedFields)
+	}
+
+	// rules that refer to other fields are not checked for missing time or bool either
+	type Test struct {
+		Start    time.Time
+		End      time.Time `validation:"req withinfield:Start,60"`
+		Agreed   bool
+		Accepted bool `validation:"req eqfield:Agreed"`
+	}
+	r := Test{Start: time.Now(), Agreed: true}
+	_, failedFields = Validate(&r, nil)
+	if failedFields["End"] != FailTime || failedFields["Accepted"] != FailBool {
+		t.Errorf("Validate returned %v where End should fail with FailTime only and Accepted with FailBool only", failedFields)
+	}
+}
+
+func TestRequiredNumbers(t *testing.T) {
+	cases := []struct {
+		value         interface{}
+		tag           string
+		expectedFlags int64
+	}{
+		{0, "req", FailZero},
+		{1, "req", 0},
+		{-1, "req", 0},
+		{0, "req valmin:0", 0},
+		{0, "req valmax:0", 0},
+		{0, "req valmin:0 valmax:10", 0},
+		{0, "req valmin:-5 valmax:5", 0},
+		{0, "req valmin:1", FailValMin},
+		{0, "req valmax:-1", FailValMax},
+		{0, "valmin:1", FailValMin},
+		{0, "", 0},
+		{uint(0), "req", FailZero},
+		{uint(0), "req valmin:0", 0},
+		{uint(0), "req valmin:1", FailValMin},
+		{0.0, "req", FailZero},
+		{0.0, "req valmin:0", 0},
+		{0.0, "req valmin:0.5", FailValMin},
+		{0.1, "req", 0},
+	}
+	for _, c := range cases {
+		_, failureFlags := ValidateField(c.value, c.tag, "")
+		if failureFlags != c.expectedFlags {
+			t.Fatalf("ValidateField returned %d where it should be %d for %v with '%s'", failureFlags, c.expectedFlags, c.value, c.tag)
+		}
+	}
+}
+
+func TestContainsExcludesWithDefaultValues(t *testing.T) {
+	s := Test33{}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Handle": FailContains,
+		"Code":   FailEmpty,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestContainsExcludesWithInvalidValues(t *testing.T) {
+	s := Test33{
+		Handle:  "admin",
+		Comment: "see https://example.com",
+		Code:    "abcx",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Handle":  FailContains | FailExcludes,
+		"Comment": FailExcludes,
+		"Code":    FailContains,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestContainsExcludesWithValidValues(t *testing.T) {
+	s := Test33{
+		Handle:  "@Admin",
+		Comment: "see example.com",
+		Code:    "abcX",
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestUnexportedFieldsWithoutOption(t *testing.T) {
+	s := Test34{
+		Name:   "John",
+		secret: "short",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"secret": FailLenMin,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	_, fieldErrors := ValidateDetailed(&s, opts)
+	if len(fieldErrors) != 1 || fieldErrors[0].Value != nil {
+		t.Errorf("ValidateDetailed returned value of unexported field without ValidateUnexported")
+	}
+}
+
+func TestUnexportedFieldsWithOption(t *testing.T) {
+	s := Test34{
+		Name:   "John",
+		secret: "short",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"secret":  FailLenMin,
+		"expires": FailTime,
+	}
+	opts := &ValidationOptions{
+		ValidateUnexported: true,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	// struct passed by value is not addressable
+	valid, failedFields := Validate(s, opts)
+	if valid || failedFields["expires"] != FailTime {
+		t.Errorf("Validate failed to validate unexported field of struct passed by value")
+	}
+
+	_, fieldErrors := ValidateDetailed(&s, opts)
+	if len(fieldErrors) != 2 || fieldErrors[0].Value != "short" {
+		t.Errorf("ValidateDetailed failed to return value of unexported field with ValidateUnexported")
+	}
+
+	s.secret = "longenough"
+	s.expires = time.Now()
+	expectedBool = true
+	expectedFailedFields = map[string]int64{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestFieldComparisonWithEqualValues(t *testing.T) {
+	s := Test35{
+		StartTs:  100,
+		EndTs:    100,
+		NotAfter: 100,
+		MinPrice: 10,
+		MaxPrice: 10,
+		Discount: 10,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"EndTs":    FailGtField,
+		"Discount": FailLtField,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestFieldComparisonWithInvalidValues(t *testing.T) {
+	s := Test35{
+		StartTs:  100,
+		EndTs:    99,
+		NotAfter: -5,
+		MinPrice: 10.5,
+		MaxPrice: 10,
+		Discount: 11,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"EndTs":    FailGtField,
+		"NotAfter": FailGtField,
+		"MinPrice": FailLtField,
+		"Discount": FailLtField,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestFieldComparisonWithValidValues(t *testing.T) {
+	s := Test35{
+		StartTs:  100,
+		EndTs:    101,
+		NotAfter: 100,
+		MinPrice: 9.5,
+		MaxPrice: 10,
+		Discount: 9.99,
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestFieldComparisonWithNonNumericField(t *testing.T) {
+	s := Test35Invalid{Name: "x", Count: 1}
+	_, _, err := ValidateE(&s, nil)
+	if err == nil {
+		t.Errorf("ValidateE did not return an error for field compared with a string")
+	}
+}
+
+func TestWithCustomEmailRegexp(t *testing.T) {
+	s := Test36{
+		Email:        "john@example.com",
+		WorkEmail:    "john@company.com",
+		PrimaryEmail: "john.doe@company.com",
+	}
+
+	// global
+	SetEmailRegexp(regexp.MustCompile(`^[a-z]+@company\.com$`))
+	defer SetEmailRegexp(nil)
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Email":        FailEmail,
+		"PrimaryEmail": FailEmail,
+	}
+	opts := &ValidationOptions{
+		ValidateWhenSuffix: true,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	// options take precedence over global
+	opts.EmailRegexp = regexp.MustCompile(`^[a-z.]+@(example|company)\.com$`)
+	expectedBool = true
+	expectedFailedFields = map[string]int64{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	// built-in is restored
+	SetEmailRegexp(nil)
+	opts.EmailRegexp = nil
+	s.Email = "john+doe@example.co.uk"
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestIPWithInvalidValues(t *testing.T) {
+	s := Test37{
+		Address:    "256.0.0.1",
+		AddressV4:  "::1",
+		AddressV6:  "10.0.0.1",
+		Network:    "10.0.0.0",
+		NetworkOpt: "10.0.0.0/33",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Address":    FailIP,
+		"AddressV4":  FailIP,
+		"AddressV6":  FailIP,
+		"Network":    FailCIDR,
+		"NetworkOpt": FailCIDR,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestIPWithValidValues(t *testing.T) {
+	s := Test37{
+		Address:    "::1",
+		AddressV4:  "10.0.0.1",
+		AddressV6:  "2001:db8::1",
+		Network:    "10.0.0.0/8",
+		NetworkOpt: "2001:db8::/32",
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestInterfaceWithNilValues(t *testing.T) {
+	var nilPort *int
+	s := Test38{
+		Port: nilPort,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Name": FailNil,
+		"Port": FailNil,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestInterfaceWithInvalidValues(t *testing.T) {
+	s := Test38{
+		Name:     "Jo",
+		Port:     70000,
+		Optional: "invalid",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Name":     FailLenMin,
+		"Port":     FailValMax,
+		"Optional": FailEmail,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestInterfaceWithValidValues(t *testing.T) {
+	name := "John"
+	s := Test38{
+		Name:     &name,
+		Port:     uint16(8080),
+		Optional: []string{"not validated"},
+		Stringer: time.Second,
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	opts.OverwriteFieldValues = map[string]interface{}{
+		"Port": 0,
+	}
+	expectedBool = false
+	expectedFailedFields = map[string]int64{
+		"Port": FailValMin,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestDigitsWithDefaultValues(t *testing.T) {
+	s := Test39{}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Pin":     FailDigits,
+		"Account": FailDigits,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestDigitsWithInvalidValues(t *testing.T) {
+	s := Test39{
+		Pin:     12345,
+		Account: 1234567890123,
+		Offset:  -100,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Pin":     FailDigits,
+		"Account": FailDigits,
+		"Offset":  FailDigits,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestDigitsWithValidValues(t *testing.T) {
+	s := Test39{
+		Pin:     -1234,
+		Account: 123456,
+		Offset:  -99,
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestFailFast(t *testing.T) {
+	s := Test33{
+		Handle:  "admin",
+		Comment: "see https://example.com",
+		Code:    "abcx",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Handle":  FailContains | FailExcludes,
+		"Comment": FailExcludes,
+		"Code":    FailContains,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	expectedFailedFields = map[string]int64{
+		"Handle":  FailContains,
+		"Comment": FailExcludes,
+		"Code":    FailContains,
+	}
+	opts = &ValidationOptions{
+		FailFast: true,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestFailFastSkipsCustomValidators(t *testing.T) {
+	called := false
+	opts := &ValidationOptions{
+		FailFast: true,
+		Validators: map[string]ValidatorFunc{
+			"even": func(value reflect.Value) (bool, int64) {
+				called = true
+				return value.Int()%2 == 0, 0
+			},
+		},
+	}
+	s := struct {
+		Number int `validation:"valmin:10 custom:even"`
+	}{Number: 3}
+	valid, failedFields := Validate(&s, opts)
+	if valid || failedFields["Number"] != FailValMin || called {
+		t.Errorf("Validate with FailFast ran custom validator on a value that already failed")
+	}
+}
+
+func TestOneOfIntWithInvalidValues(t *testing.T) {
+	s := Test40{
+		Status: 201,
+		Offset: -2,
+		Role:   4,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Status": FailOneOf,
+		"Offset": FailOneOf,
+		"Role":   FailOneOf,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestOneOfIntWithValidValues(t *testing.T) {
+	s := Test40{
+		Status: 404,
+		Offset: -1,
+		Role:   3,
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestOneOfIntWithInvalidTag(t *testing.T) {
+	s := struct {
+		Status int `validation:"oneofint:200,OK"`
+	}{}
+	_, _, err := ValidateE(&s, nil)
+	if err == nil {
+		t.Errorf("ValidateE did not return an error for invalid oneofint value")
+	}
+}
+
+func TestWithConditions(t *testing.T) {
+	s := Test1{
+		FirstName:     "Johnny",
+		LastName:      "Smith",
+		Age:           30,
+		Price:         100,
+		PostCode:      "00-000",
+		Email:         "john@example.com",
+		BelowZero:     -4,
+		DiscountPrice: 50,
+		Country:       "GB",
+	}
+	opts := &ValidationOptions{
+		Conditions: map[string]func(obj interface{}) bool{
+			// discount price is validated only when there is a discount
+			"DiscountPrice": func(obj interface{}) bool {
+				return obj.(*Test1).DiscountPrice != obj.(*Test1).Price
+			},
+		},
+	}
+
+	s.DiscountPrice = 9999
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"DiscountPrice": FailValMax,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s.Price = 9999
+	expectedBool = true
+	expectedFailedFields = map[string]int64{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestBase64AndHexWithDefaultValues(t *testing.T) {
+	s := Test41{}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Token": FailEmpty,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestBase64AndHexWithInvalidValues(t *testing.T) {
+	// missing padding, standard alphabet in URL-safe encoding and odd number of digits
+	s := Test41{
+		Token:    "aGVsbG8",
+		URLToken: "+/+/",
+		Checksum: "abc",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Token":    FailBase64,
+		"URLToken": FailBase64,
+		"Checksum": FailHex,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	// invalid characters
+	s = Test41{
+		Token:    "aGVsbG8=!",
+		URLToken: "aGk=",
+		Checksum: "zz",
+	}
+	expectedFailedFields = map[string]int64{
+		"Token":    FailBase64,
+		"Checksum": FailHex,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestBase64AndHexWithValidValues(t *testing.T) {
+	data := []byte{0xfb, 0xff, 0x00, 'h', 'i'}
+	s := Test41{
+		Token:    base64.StdEncoding.EncodeToString(data),
+		URLToken: base64.URLEncoding.EncodeToString(data),
+		Checksum: hex.EncodeToString(data),
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestBetweenWithDefaultValues(t *testing.T) {
+	s := Test42{}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Score": FailValMin,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestBetweenWithInvalidValues(t *testing.T) {
+	s := Test42{
+		Score:   21,
+		Zero:    -1,
+		Delta:   -1.6,
+		Percent: 101,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Score":   FailValMax,
+		"Zero":    FailValMin,
+		"Delta":   FailValMin,
+		"Percent": FailValMax,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s.Zero = 1
+	expectedFailedFields["Zero"] = FailValMax
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestBetweenWithValidValues(t *testing.T) {
+	s := Test42{
+		Score:   20,
+		Delta:   1.5,
+		Percent: 0,
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestBetweenWithInvalidTag(t *testing.T) {
+	for _, tag := range []string{"between:20,10", "between:10", "between:a,b"} {
+		_, _, err := ValidateE(&Test1{}, &ValidationOptions{
+			OverwriteFieldTags: map[string]map[string]string{
+				"Age": {"validation": tag},
+			},
+		})
+		if err == nil {
+			t.Errorf("ValidateE did not return an error for invalid tag %s", tag)
+		}
+	}
+}
+
+func TestNamedTypesWithInvalidValues(t *testing.T) {
+	s := Test43{
+		Status:  "ok",
+		Age:     17,
+		Tags:    Test43Tags{"a", "b", "c"},
+		Minimum: 18,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Status":  FailLenMin | FailOneOf,
+		"Age":     FailValMin,
+		"Tags":    FailLenMax,
+		"Minimum": FailLtField,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestNamedTypesWithValidValues(t *testing.T) {
+	s := Test43{
+		Status:  "active",
+		Age:     18,
+		Tags:    Test43Tags{"a"},
+		Minimum: 18,
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	// values of underlying types are converted
+	opts.OverwriteFieldValues = map[string]interface{}{
+		"Status": "new",
+		"Age":    30,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestWithSuffixRules(t *testing.T) {
+	s := Test44{
+		PostSlug:     "Hello-World",
+		CategorySlug: "news_2024",
+		PrimaryEmail: "invalid",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"PostSlug":     FailLowercase | FailRegexp,
+		"CategorySlug": FailRegexp,
+		"PrimaryEmail": FailEmail,
+	}
+	opts := &ValidationOptions{
+		ValidateWhenSuffix: true,
+		SuffixRules: map[string]string{
+			"Slug": "lowercase regexp:^[a-z0-9-]*$",
+		},
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s.PostSlug = "hello-world"
+	s.CategorySlug = "news-2024"
+	s.PrimaryEmail = "john@example.com"
+	expectedBool = true
+	expectedFailedFields = map[string]int64{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	// suffix rules are not used without ValidateWhenSuffix
+	s.PostSlug = "Hello"
+	opts.ValidateWhenSuffix = false
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestWithDisabledBuiltinSuffixRules(t *testing.T) {
+	s := struct {
+		AdjustmentPrice int
+		PrimaryEmail    string
+		HomepageURL     string
+	}{
+		AdjustmentPrice: -50,
+		PrimaryEmail:    "invalid",
+		HomepageURL:     "invalid",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"AdjustmentPrice": FailValMin,
+		"PrimaryEmail":    FailEmail,
+		"HomepageURL":     FailURL,
+	}
+	opts := &ValidationOptions{
+		ValidateWhenSuffix: true,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	expectedFailedFields = map[string]int64{
+		"PrimaryEmail": FailEmail,
+	}
+	opts = &ValidationOptions{
+		ValidateWhenSuffix:        true,
+		DisableBuiltinSuffixRules: true,
+		SuffixRules: map[string]string{
+			"Email": "email",
+		},
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestMapEntriesWithInvalidValues(t *testing.T) {
+	s := Test45{
+		Meta: map[string]string{
+			"valid":  "ok",
+			"badKey": "ok",
+			"long":   "toolong",
+			"Both":   "",
+		},
+		Scores: map[int]int{
+			0: 50,
+			1: 101,
+			2: 100,
+		},
+		Extra: map[string]interface{}{
+			"a": "x",
+			"b": nil,
+			"c": "xy",
+		},
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Meta":         FailLenMax,
+		"Meta[Both]":   FailRegexp | FailEmpty,
+		"Meta[badKey]": FailRegexp,
+		"Meta[long]":   FailLenMax,
+		"Scores[0]":    FailValMin,
+		"Scores[1]":    FailValMax,
+		"Extra[a]":     FailLenMin,
+		"Extra[b]":     FailNil,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	// entries are in order of keys
+	_, failures := ValidateOrdered(&s, opts)
+	fields := []string{}
+	for _, failure := range failures {
+		fields = append(fields, failure.Field)
+	}
+	expectedFields := []string{"Meta", "Meta[Both]", "Meta[badKey]", "Meta[long]", "Scores[0]", "Scores[1]", "Extra[a]", "Extra[b]"}
+	if !reflect.DeepEqual(fields, expectedFields) {
+		t.Errorf("ValidateOrdered returned %v where it should be %v", fields, expectedFields)
+	}
+}
+
+func TestMapEntriesWithValidValues(t *testing.T) {
+	s := Test45{
+		Meta: map[string]string{
+			"valid": "ok",
+		},
+		Scores: map[int]int{
+			1: 100,
+		},
+		Extra: map[string]interface{}{
+			"a": "xy",
+		},
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestWithStrictTags(t *testing.T) {
+	s := struct {
+		Name string `validation:"req lenmn:3"`
+	}{Name: "Jo"}
+
+	// unknown rule is ignored by default
+	_, _, err := ValidateE(&s, nil)
+	if err != nil {
+		t.Errorf("ValidateE returned an error for unknown rule without StrictTags")
+	}
+
+	_, _, err = ValidateE(&s, &ValidationOptions{StrictTags: true})
+	if err == nil || !strings.Contains(err.Error(), "lenmn:3") {
+		t.Errorf("ValidateE did not return an error for misspelled rule with StrictTags")
+	}
+
+	// all rules in Test1 are known
+	_, _, err = ValidateE(&Test1{}, &ValidationOptions{StrictTags: true})
+	if err != nil {
+		t.Errorf("ValidateE returned an error for known rules with StrictTags: %s", err)
+	}
+}
+
+func TestDecimalWithInvalidValues(t *testing.T) {
+	opts := &ValidationOptions{}
+	expectedBool := false
+	for _, amount := range []string{"19.999", "019.99", "19.", ".99", "-", "1,99", "19.9a", "+19"} {
+		s := Test46{
+			Amount:   amount,
+			Quantity: "1.5",
+		}
+		expectedFailedFields := map[string]int64{
+			"Amount":   FailDecimal,
+			"Quantity": FailDecimal,
+		}
+		compare(&s, expectedBool, expectedFailedFields, opts, t)
+	}
+}
+
+func TestDecimalWithValidValues(t *testing.T) {
+	opts := &ValidationOptions{}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	for _, amount := range []string{"19", "19.9", "19.99", "0.99", "0", "-19.99", "-0.5"} {
+		s := Test46{
+			Amount:   amount,
+			Quantity: "-10",
+		}
+		compare(&s, expectedBool, expectedFailedFields, opts, t)
+	}
+}
+
+func TestSliceOfStructsWithInvalidValues(t *testing.T) {
+	s := Test47{
+		Items: []Test47Item{
+			{Name: "Apple", Quantity: 1},
+			{Name: "", Quantity: 0},
+		},
+		Previous: []*Test47Item{
+			nil,
+			{Name: "Pear", Quantity: -1},
+		},
+		Fixed: [2]Test47Item{
+			{Name: "Plum", Quantity: 1},
+			{Name: "Kiwi", Quantity: 2},
+		},
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Items[1].Name":        FailEmpty,
+		"Items[1].Quantity":    FailValMin,
+		"Previous[1].Quantity": FailValMin,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestSliceOfStructsWithEmptySlice(t *testing.T) {
+	s := Test47{
+		Fixed: [2]Test47Item{
+			{Name: "Plum", Quantity: 1},
+			{Name: "Kiwi", Quantity: 2},
+		},
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Items": FailEmpty,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestSliceOfStructsWithValidValues(t *testing.T) {
+	s := Test47{
+		Items: []Test47Item{
+			{Name: "Apple", Quantity: 1},
+		},
+		Fixed: [2]Test47Item{
+			{Name: "Plum", Quantity: 1},
+			{Name: "Kiwi", Quantity: 2},
+		},
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestOmitEmptyWithAbsentFields(t *testing.T) {
+	s := Test48{}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Name":    FailEmpty,
+		"Email":   FailEmail,
+		"Website": FailNil,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	delete(expectedFailedFields, "Email")
+	opts = &ValidationOptions{
+		OmitEmpty: true,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestOmitEmptyWithPresentInvalidFields(t *testing.T) {
+	age := 17
+	website := "invalid"
+	s := Test48{
+		Name:     "Jo",
+		Nickname: "J1",
+		Email:    "invalid",
+		Age:      &age,
+		Website:  &website,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Name":     FailLenMin,
+		"Nickname": FailLenMin | FailAlpha,
+		"Email":    FailEmail,
+		"Age":      FailValMin,
+		"Website":  FailURL,
+	}
+	opts := &ValidationOptions{
+		OmitEmpty: true,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestJSONWithDefaultValues(t *testing.T) {
+	s := Test49{}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Payload": FailEmpty,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestJSONWithInvalidValues(t *testing.T) {
+	s := Test49{
+		Payload:  `{"a": 1,}`,
+		Metadata: `[1, 2`,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Payload":  FailJSON,
+		"Metadata": FailJSON,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestJSONWithValidValues(t *testing.T) {
+	s := Test49{
+		Payload:  `{"a": 1, "b": [true, null]}`,
+		Metadata: `[1, "two"]`,
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestSignRules(t *testing.T) {
+	opts := &ValidationOptions{}
+	tests := []struct {
+		value                int
+		expectedFailedFields map[string]int64
+	}{
+		{-1, map[string]int64{"Positive": FailValMin, "NonNegative": FailValMin}},
+		{0, map[string]int64{"Positive": FailValMin, "Negative": FailValMax}},
+		{1, map[string]int64{"Negative": FailValMax, "NonPositive": FailValMax}},
+	}
+	for _, test := range tests {
+		s := Test50{
+			Positive:    test.value,
+			NonNegative: int64(test.value),
+			Negative:    float64(test.value),
+			NonPositive: int8(test.value),
+		}
+		compare(&s, false, test.expectedFailedFields, opts, t)
+	}
+
+	// zero is a value when number is required
+	s := struct {
+		Count uint `validation:"req nonneg"`
+	}{}
+	compare(&s, true, map[string]int64{}, opts, t)
+}
+
+func TestRuneLenWithInvalidValues(t *testing.T) {
+	s := Test51{
+		Bytes: "café",
+		Runes: "ééééé",
+		Code:  "😀😀",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Bytes": FailLenMax,
+		"Runes": FailLenMax,
+		"Code":  FailLen,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s = Test51{
+		Bytes: "ab",
+		Runes: "😀",
+		Code:  "abc",
+	}
+	expectedFailedFields = map[string]int64{
+		"Runes": FailLenMin,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestRuneLenWithValidValues(t *testing.T) {
+	s := Test51{
+		Bytes: "cafe",
+		Runes: "😀😀😀😀",
+		Code:  "žlu",
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestValidateWithSchema(t *testing.T) {
+	s := Test1{
+		FirstName:     "Johnny",
+		LastName:      "Smith",
+		Age:           30,
+		PostCode:      "43-155",
+		Email:         "john@example.com",
+		BelowZero:     -4,
+		DiscountPrice: 8000,
+		Country:       "GB",
+	}
+	schema := map[string]string{
+		"FirstName": "req lenmax:3",
+		"Country":   "oneof:PL,DE",
+		"County":    "req",
+	}
+	expectedFailedFields := map[string]int{
+		"FirstName": FailLenMax,
+		"Country":   FailOneOf | FailRegexp,
+		"County":    FailEmpty,
+	}
+	opts := &ValidationOptions{
+		OverwriteFieldTags: map[string]map[string]string{
+			"FirstName": {"validation": "req lenmin:10"},
+			"Country":   {"validation_regexp": "^[A-Z]$"},
+		},
+	}
+	valid, failedFields := ValidateWithSchema(&s, schema, opts)
+	if valid || !reflect.DeepEqual(failedFields, expectedFailedFields) {
+		t.Errorf("ValidateWithSchema returned %v where it should be %v", failedFields, expectedFailedFields)
+	}
+
+	// options are not modified
+	if opts.OverwriteFieldTags["FirstName"]["validation"] != "req lenmin:10" || len(opts.OverwriteFieldTags) != 2 {
+		t.Errorf("ValidateWithSchema modified OverwriteFieldTags in options")
+	}
+}
+
+func TestValidateReport(t *testing.T) {
+	name := "John"
+	s := struct {
+		Name     *string `validation:"req lenmin:5"`
+		Nickname string  `validation:"omitempty lenmin:3"`
+		Age      int     `validation:"valmin:18"`
+		Ratio    complex128
+		Settings Test8Profile
+		Skipped  string `validation:"req"`
+		Profile  *Test8Profile
+	}{
+		Name:    &name,
+		Age:     20,
+		Profile: &Test8Profile{Age: 18},
+	}
+	expectedFailedFields := map[string]int{
+		"Name":               FailLenMin,
+		"Settings.FirstName": FailEmpty,
+		"Settings.Age":       FailValMin,
+		"Profile.FirstName":  FailEmpty,
+	}
+	expectedEvaluatedFields := map[string]bool{
+		"Name":               true,
+		"Age":                true,
+		"Settings.FirstName": true,
+		"Settings.Age":       true,
+		"Profile.FirstName":  true,
+		"Profile.Age":        true,
+	}
+	opts := &ValidationOptions{
+		SkipFields: map[string]bool{
+			"Skipped": true,
+		},
+	}
+	valid, failedFields, evaluatedFields := ValidateReport(&s, opts)
+	if valid || !reflect.DeepEqual(failedFields, expectedFailedFields) {
+		t.Errorf("ValidateReport returned %v where it should be %v", failedFields, expectedFailedFields)
+	}
+	if !reflect.DeepEqual(evaluatedFields, expectedEvaluatedFields) {
+		t.Errorf("ValidateReport returned evaluated fields %v where it should be %v", evaluatedFields, expectedEvaluatedFields)
+	}
+}
+
+func TestChanAndFuncWithNilValues(t *testing.T) {
+	s := Test53{}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Events":  FailNil,
+		"Handler": FailNil,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestChanAndFuncWithValidValues(t *testing.T) {
+	s := Test53{
+		Events:  make(chan string),
+		Handler: func() {},
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestDateTimeWithInvalidValues(t *testing.T) {
+	s := Test54{
+		BirthDate: "2021-13-40",
+		CreatedAt: "2021-01-02T10:00:00Z",
+		Expiry:    "2021-01",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"BirthDate": FailDateTime,
+		"CreatedAt": FailDateTime,
+		"Expiry":    FailDateTime,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s = Test54{}
+	expectedFailedFields = map[string]int64{
+		"BirthDate": FailEmpty,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestDateTimeWithValidValues(t *testing.T) {
+	s := Test54{
+		BirthDate: "2021-02-28",
+		CreatedAt: "2021-01-02 10:00:00",
+		Expiry:    "12/27",
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{StrictTags: true}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestEmbeddedPointerWithNilValues(t *testing.T) {
+	s := Test55{Title: "Post"}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Test55Base": FailNil,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	expectedBool = true
+	expectedFailedFields = map[string]int64{}
+	opts = &ValidationOptions{
+		SkipFields: map[string]bool{
+			"Test55Base": true,
+		},
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestEmbeddedPointerWithPopulatedValues(t *testing.T) {
+	s := Test55{
+		Test55Base:  &Test55Base{Name: "ab"},
+		Test55Audit: &Test55Audit{},
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"ID":        FailZero,
+		"Name":      FailLenMin,
+		"CreatedBy": FailEmpty,
+		"Title":     FailEmpty,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s = Test55{
+		Test55Base:  &Test55Base{ID: 1, Name: "abc"},
+		Test55Audit: &Test55Audit{CreatedBy: "admin"},
+		Title:       "Post",
+	}
+	expectedBool = true
+	expectedFailedFields = map[string]int64{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestEmptyValuesWithSentinels(t *testing.T) {
+	s := Test56{
+		Score:    -1,
+		Rank:     -1,
+		Country:  "N/A",
+		Nickname: "N/A",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Score":   FailZero,
+		"Rank":    FailValMin,
+		"Country": FailEmpty,
+	}
+	opts := &ValidationOptions{
+		EmptyValues: map[reflect.Kind][]interface{}{
+			reflect.String: {"N/A"},
+		},
+		EmptyFieldValues: map[string][]interface{}{
+			"Score": {-1},
+		},
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	// without the options, sentinels are validated as they are
+	expectedFailedFields = map[string]int64{
+		"Rank": FailValMin,
+	}
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{}, t)
+}
+
+func TestEmptyValuesWithValidValues(t *testing.T) {
+	s := Test56{
+		Score:   0,
+		Rank:    1,
+		Country: "PL",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Score": FailZero,
+	}
+	opts := &ValidationOptions{
+		EmptyFieldValues: map[string][]interface{}{
+			"Score": {-1},
+		},
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s.Score = 10
+	expectedBool = true
+	expectedFailedFields = map[string]int64{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestRulesFor(t *testing.T) {
+	rules := RulesFor(&Test1{}, nil)
+	if len(rules) != 10 {
+		t.Errorf("RulesFor returned %d fields where it should be 10", len(rules))
+	}
+	firstName := rules["FirstName"]
+	if firstName == nil || firstName.Flags&Required == 0 || firstName.LenMin != 5 || firstName.LenMax != 25 {
+		t.Errorf("RulesFor returned invalid rules for FirstName: %+v", firstName)
+	}
+	age := rules["Age"]
+	if age == nil || age.ValMin != 18 || age.ValMax != 150 {
+		t.Errorf("RulesFor returned invalid rules for Age: %+v", age)
+	}
+	postCode := rules["PostCode"]
+	if postCode == nil || postCode.Regexp == nil || postCode.Regexp.String() != "^[0-9][0-9]-[0-9][0-9][0-9]$" {
+		t.Errorf("RulesFor returned invalid rules for PostCode: %+v", postCode)
+	}
+	if rules["Email"] == nil || rules["Email"].Flags&Email == 0 {
+		t.Errorf("RulesFor returned invalid rules for Email: %+v", rules["Email"])
+	}
+
+	// promoted fields of embedded pointer are included even when it is nil
+	rules = RulesFor((*Test55)(nil), nil)
+	if rules["ID"] == nil || rules["CreatedBy"] == nil || rules["Title"] == nil || rules["Test55Base"] != nil {
+		t.Errorf("RulesFor returned invalid fields for embedded structs: %v", rules)
+	}
+}
+
+func TestRulesForWithOverwrittenTags(t *testing.T) {
+	opts := &ValidationOptions{
+		OverwriteTagName: "mytag",
+		OverwriteFieldTags: map[string]map[string]string{
+			"FirstName": {
+				"mytag": "req lenmin:2 lenmax:10",
+			},
+		},
+	}
+	rules := RulesFor(Test2{}, opts)
+	if rules["FirstName"] == nil || rules["FirstName"].LenMin != 2 || rules["FirstName"].LenMax != 10 {
+		t.Errorf("RulesFor returned invalid rules for FirstName: %+v", rules["FirstName"])
+	}
+	if rules["LastName"] == nil || rules["LastName"].Flags&Required == 0 || rules["LastName"].LenMin != 2 {
+		t.Errorf("RulesFor returned invalid rules for LastName: %+v", rules["LastName"])
+	}
+
+	// returned rules are copies so modifying them does not change the cached ones
+	rules["LastName"].LenMin = 100
+	if RulesFor(Test2{}, opts)["LastName"].LenMin != 2 {
+		t.Errorf("RulesFor returned rules that modify the cache")
+	}
+}
+
+func TestWithinFieldWithInvalidValues(t *testing.T) {
+	start := time.Date(2021, 1, 2, 10, 0, 0, 0, time.UTC)
+	s := Test57{
+		StartTs:   1600000000,
+		EndTs:     1600003601,
+		Target:    10,
+		Measured:  9.25,
+		StartTime: start,
+		EndTime:   start.Add(-61 * time.Second),
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"EndTs":    FailWithinField,
+		"Measured": FailWithinField,
+		"EndTime":  FailWithinField,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestWithinFieldWithValidValues(t *testing.T) {
+	start := time.Date(2021, 1, 2, 10, 0, 0, 0, time.UTC)
+	s := Test57{
+		StartTs:   1600000000,
+		EndTs:     1600003600,
+		Target:    10,
+		Measured:  10.5,
+		StartTime: start,
+		EndTime:   start.Add(60 * time.Second),
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{StrictTags: true}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestWithinFieldWithInvalidTag(t *testing.T) {
+	s := Test57{}
+	opts := &ValidationOptions{
+		OverwriteFieldTags: map[string]map[string]string{
+			"EndTs": {
+				"validation": "withinfield:StartTs",
+			},
+		},
+	}
+	_, _, err := ValidateE(&s, opts)
+	if err == nil {
+		t.Errorf("ValidateE did not return an error for withinfield without delta")
+	}
+}
+
+func TestCreditCardWithInvalidValues(t *testing.T) {
+	s := Test58{
+		CardNumber: "4111 1111 1111 1112",
+		BackupCard: "4111-1111-ABCD-1111",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"CardNumber": FailCreditCard,
+		"BackupCard": FailCreditCard,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s = Test58{
+		CardNumber: "0",
+		BackupCard: "42424242424242424242",
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestCreditCardWithValidValues(t *testing.T) {
+	s := Test58{
+		CardNumber: "4111 1111 1111 1111",
+		BackupCard: "5555-5555-5555-4444",
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s.BackupCard = "378282246310005"
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestHostnameWithInvalidValues(t *testing.T) {
+	s := Test59{
+		Hostname: "a.b-c.com.",
+		Domain:   "localhost",
+		DBHost:   "-db.local",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Hostname": FailHostname,
+		"Domain":   FailFQDN,
+		"DBHost":   FailHostname,
+	}
+	opts := &ValidationOptions{ValidateWhenSuffix: true}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s = Test59{
+		Hostname: strings.Repeat("a", 64) + ".com",
+		Domain:   "example-.com",
+		DBHost:   "db_1.local",
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestHostnameWithValidValues(t *testing.T) {
+	s := Test59{
+		Hostname: "a.b-c.com",
+		Domain:   "a.b-c.com.",
+		DBHost:   strings.Repeat("a", 63) + ".local",
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{ValidateWhenSuffix: true}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s.Hostname = "localhost"
+	s.Domain = "example.com"
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestValidateDiff(t *testing.T) {
+	original := Test1{
+		FirstName: "Jo",
+		LastName:  "Smith",
+		Age:       30,
+		PostCode:  "43-155",
+		Email:     "legacy",
+		Country:   "GB",
+		BelowZero: -4,
+	}
+	updated := original
+	updated.LastName = "S"
+	updated.Age = 35
+
+	// FirstName and Email are invalid but they did not change
+	expectedFailedFields := map[string]int{
+		"LastName": FailLenMin,
+	}
+	valid, failedFields := ValidateDiff(&original, &updated, nil)
+	if valid || !reflect.DeepEqual(failedFields, expectedFailedFields) {
+		t.Errorf("ValidateDiff returned %v where it should be %v", failedFields, expectedFailedFields)
+	}
+
+	// only changed fields from RestrictFields are validated
+	expectedFailedFields = map[string]int{}
+	valid, failedFields = ValidateDiff(original, updated, &ValidationOptions{
+		RestrictFields: map[string]bool{"Age": true, "FirstName": true},
+	})
+	if !valid || !reflect.DeepEqual(failedFields, expectedFailedFields) {
+		t.Errorf("ValidateDiff returned %v where it should be %v", failedFields, expectedFailedFields)
+	}
+
+	valid, failedFields = ValidateDiff(&original, &original, nil)
+	if !valid || len(failedFields) != 0 {
+		t.Errorf("ValidateDiff returned %v for unchanged object", failedFields)
+	}
+
+	expectedFailedFields = map[string]int{
+		"FirstName": FailLenMin,
+		"LastName":  FailLenMin,
+		"Email":     FailEmail,
+	}
+	valid, failedFields = ValidateDiff((*Test1)(nil), &updated, nil)
+	if valid || !reflect.DeepEqual(failedFields, expectedFailedFields) {
+		t.Errorf("ValidateDiff returned %v where it should be %v", failedFields, expectedFailedFields)
+	}
+}
+
+func TestValidateDiffWithFieldReferences(t *testing.T) {
+	type Test struct {
+		Password        string
+		PasswordConfirm string `validation:"eqfield:Password"`
+		Email           string `validation:"required_without:Phone"`
+		Phone           string
+	}
+	original := Test{Password: "secret", PasswordConfirm: "secret", Phone: "123"}
+
+	// fields which rules refer to a changed field are validated too
+	updated := original
+	updated.Password = "changed"
+	updated.Phone = ""
+	expectedFailedFields := map[string]int{
+		"PasswordConfirm": FailEqField,
+		"Email":           FailEmpty,
+	}
+	valid, failedFields := ValidateDiff(&original, &updated, nil)
+	if valid || !reflect.DeepEqual(failedFields, expectedFailedFields) {
+		t.Errorf("ValidateDiff returned %v where it should be %v", failedFields, expectedFailedFields)
+	}
+
+	// nil original means that all the fields changed
+	valid, failedFields = ValidateDiff(nil, &updated, nil)
+	if valid || !reflect.DeepEqual(failedFields, expectedFailedFields) {
+		t.Errorf("ValidateDiff returned %v for nil original where it should be %v", failedFields, expectedFailedFields)
+	}
+}
+
+func TestValidateDiffWithEmbeddedStructs(t *testing.T) {
+	original := Test55{Title: "Post"}
+	updated := Test55{
+		Test55Base: &Test55Base{ID: 1},
+		Title:      "Post",
+	}
+	expectedFailedFields := map[string]int{
+		"Name": FailEmpty,
+	}
+	valid, failedFields := ValidateDiff(&original, &updated, nil)
+	if valid || !reflect.DeepEqual(failedFields, expectedFailedFields) {
+		t.Errorf("ValidateDiff returned %v where it should be %v", failedFields, expectedFailedFields)
+	}
+
+	expectedFailedFields = map[string]int{
+		"Test55Base": FailNil,
+	}
+	valid, failedFields = ValidateDiff(&updated, &original, nil)
+	if valid || !reflect.DeepEqual(failedFields, expectedFailedFields) {
+		t.Errorf("ValidateDiff returned %v where it should be %v", failedFields, expectedFailedFields)
+	}
+}
+
+func TestNullableWithNullValues(t *testing.T) {
+	s := Test60{}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Name": FailNil,
+		"Age":  FailNil,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestNullableWithInvalidValues(t *testing.T) {
+	s := Test60{
+		Name:     sql.NullString{String: "Jo", Valid: true},
+		Age:      sql.NullInt64{Int64: 151, Valid: true},
+		Score:    sql.NullFloat64{Float64: 10.5, Valid: true},
+		Nickname: sql.NullString{String: "x", Valid: false},
+		Level:    sql.Null[int]{V: 0, Valid: true},
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Name":  FailLenMin,
+		"Age":   FailValMax,
+		"Score": FailValMax,
+		"Level": FailValMin,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestNullableWithValidValues(t *testing.T) {
+	s := Test60{
+		Name:  sql.NullString{String: "John", Valid: true},
+		Age:   sql.NullInt64{Int64: 0, Valid: true},
+		Level: sql.Null[int]{V: 2, Valid: true},
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestOneOfCIWithInvalidValues(t *testing.T) {
+	s := Test61{
+		Status:   "Archived",
+		Category: "News",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Status":   FailOneOf,
+		"Category": FailOneOf,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestOneOfCIWithValidValues(t *testing.T) {
+	s := Test61{
+		Status:   "Draft",
+		Category: "news",
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{StrictTags: true}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s.Status = "PUBLISHED"
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestMultipleOfWithInvalidValues(t *testing.T) {
+	s := Test62{
+		Quantity: 13,
+		Offset:   -7,
+		Packs:    13,
+		Weight:   0.35,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Quantity": FailMultipleOf,
+		"Offset":   FailMultipleOf,
+		"Packs":    FailMultipleOf,
+		"Weight":   FailMultipleOf,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestMultipleOfWithValidValues(t *testing.T) {
+	s := Test62{
+		Quantity: 24,
+		Offset:   -10,
+		Packs:    18,
+		Weight:   0.3,
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{StrictTags: true}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s.Quantity = -36
+	s.Offset = 15
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestMultipleOfWithZero(t *testing.T) {
+	_, _, err := ValidateE(&Test62{}, &ValidationOptions{
+		OverwriteFieldTags: map[string]map[string]string{
+			"Quantity": {
+				"validation": "multipleof:0",
+			},
+		},
+	})
+	if err == nil {
+		t.Errorf("ValidateE did not return an error for multipleof:0")
+	}
+}
+
+func TestRestrictFieldsWithNestedPaths(t *testing.T) {
+	score := 11
+	s := Test8{
+		Profile:         &Test8Profile{FirstName: "J", Age: 15},
+		OptionalProfile: &Test8Profile{FirstName: "J", Age: 15},
+		Score:           &score,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Profile.Age":               FailValMin,
+		"OptionalProfile.FirstName": FailLenMin,
+		"OptionalProfile.Age":       FailValMin,
+	}
+	opts := &ValidationOptions{
+		RestrictFields: map[string]bool{
+			"Profile.Age":     true,
+			"OptionalProfile": true,
+		},
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	// nil nested struct fails for the field itself when it is required
+	s.Profile = nil
+	expectedFailedFields = map[string]int64{
+		"Profile": FailNil,
+	}
+	opts = &ValidationOptions{
+		RestrictFields: map[string]bool{
+			"Profile.Age": true,
+		},
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestRestrictFieldsWithNestedPathsInSlices(t *testing.T) {
+	s := Test47{
+		Items: []Test47Item{{Name: "", Quantity: 0}, {Name: "Pen", Quantity: -1}},
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Items[0].Quantity": FailValMin,
+		"Items[1].Quantity": FailValMin,
+	}
+	opts := &ValidationOptions{
+		RestrictFields: map[string]bool{
+			"Items.Quantity": true,
+		},
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestPrefixWithType(t *testing.T) {
+	user := Test1{
+		FirstName: "J",
+		LastName:  "Smith",
+		Age:       30,
+		PostCode:  "43-155",
+		Email:     "john@example.com",
+		Country:   "GB",
+		BelowZero: -4,
+	}
+	profile := Test8Profile{FirstName: "J", Age: 15}
+	opts := &ValidationOptions{PrefixWithType: true}
+
+	expectedFailedFields := map[string]int{
+		"Test1.FirstName":        FailLenMin,
+		"Test8Profile.FirstName": FailLenMin,
+		"Test8Profile.Age":       FailValMin,
+	}
+	failedFields := map[string]int{}
+	for _, obj := range []interface{}{&user, profile} {
+		valid, objFailedFields := Validate(obj, opts)
+		if valid {
+			t.Errorf("Validate returned invalid boolean value for %T", obj)
+		}
+		for key, flags := range objFailedFields {
+			failedFields[key] = flags
+		}
+	}
+	if !reflect.DeepEqual(failedFields, expectedFailedFields) {
+		t.Errorf("Validate returned %v where it should be %v", failedFields, expectedFailedFields)
+	}
+
+	// nested structs get the prefix of the validated struct only
+	s := Test8{Profile: &Test8Profile{FirstName: "J", Age: 20}}
+	expectedFailedFields = map[string]int{
+		"Test8.Profile.FirstName": FailLenMin,
+		"Test8.Score":             FailNil,
+	}
+	_, failedFields = Validate(&s, opts)
+	if !reflect.DeepEqual(failedFields, expectedFailedFields) {
+		t.Errorf("Validate returned %v where it should be %v", failedFields, expectedFailedFields)
+	}
+}
+
+func TestRangesWithInvalidValues(t *testing.T) {
+	s := Test63{
+		Status:      404,
+		Temperature: 0,
+		Port:        8080 + 1000,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Status":      FailRanges,
+		"Temperature": FailRanges,
+		"Port":        FailRanges,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestRangesWithValidValues(t *testing.T) {
+	s := Test63{
+		Status:      301,
+		Temperature: -10,
+		Port:        80,
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{StrictTags: true}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s.Status = 200
+	s.Temperature = 30
+	s.Port = 8999
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestRangesWithInvalidTag(t *testing.T) {
+	for _, tag := range []string{"ranges:300-200", "ranges:1-2,abc", "ranges:"} {
+		_, _, err := ValidateE(&Test63{}, &ValidationOptions{
+			OverwriteFieldTags: map[string]map[string]string{
+				"Status": {
+					"validation": tag,
+				},
+			},
+		})
+		if err == nil {
+			t.Errorf("ValidateE did not return an error for '%s'", tag)
+		}
+	}
+}
+
+func toSnakeCase(field reflect.StructField) string {
+	var b strings.Builder
+	for i, r := range field.Name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteRune('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func TestFieldNameFunc(t *testing.T) {
+	s := Test1{
+		FirstName: "J",
+		LastName:  "S",
+		Age:       30,
+		PostCode:  "43-155",
+		Email:     "john@example.com",
+		Country:   "GB",
+		BelowZero: -4,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"first_name": FailLenMin,
+		"last_name":  FailLenMin,
+	}
+	opts := &ValidationOptions{FieldNameFunc: toSnakeCase}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	expectedFailedFields = map[string]int64{
+		"last_name": FailLenMin,
+	}
+	opts = &ValidationOptions{
+		FieldNameFunc: toSnakeCase,
+		RestrictFields: map[string]bool{
+			"last_name": true,
+			"LastName":  true,
+			"FirstName": true,
+		},
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestFieldNameFuncWithNestedStructs(t *testing.T) {
+	s := Test8{
+		Profile:         &Test8Profile{FirstName: "J", Age: 15},
+		OptionalProfile: &Test8Profile{FirstName: "J", Age: 15},
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"profile.age":                 FailValMin,
+		"optional_profile.first_name": FailLenMin,
+		"optional_profile.age":        FailValMin,
+	}
+	opts := &ValidationOptions{
+		FieldNameFunc: toSnakeCase,
+		RestrictFields: map[string]bool{
+			"profile.age":      true,
+			"optional_profile": true,
+		},
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestCharsetWithInvalidValues(t *testing.T) {
+	s := Test64{
+		Token:    "abcdefg",
+		Comment:  "<b>bold</b>",
+		Nickname: "ąę_",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Token":    FailCharset,
+		"Comment":  FailCharsetNot,
+		"Nickname": FailCharsetNot,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s.Nickname = "ąęa"
+	expectedFailedFields["Nickname"] = FailCharset
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestCharsetWithValidValues(t *testing.T) {
+	s := Test64{
+		Token:    "deadbeef01",
+		Comment:  "Fine, thanks!",
+		Nickname: "żźąę",
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{StrictTags: true}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestElementsWithInvalidValues(t *testing.T) {
+	alias := "x"
+	s := Test65{
+		Codes:   []string{"AB", "CDE", "fghijk"},
+		Scores:  [3]int{10, 101, -1},
+		Aliases: []*string{nil, &alias},
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Codes[2]":   FailLenMax | FailUppercase,
+		"Scores[1]":  FailValMax,
+		"Scores[2]":  FailValMin,
+		"Aliases[0]": FailNil,
+		"Aliases[1]": FailLenMin,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	// rules in validation tag apply to the slice itself
+	s = Test65{
+		Codes: []string{"A", "B", "C", ""},
+	}
+	expectedFailedFields = map[string]int64{
+		"Codes":    FailLenMax,
+		"Codes[3]": FailEmpty,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestElementsWithValidValues(t *testing.T) {
+	alias := "xy"
+	s := Test65{
+		Codes:   []string{"AB", "CDE", "FGHIJ"},
+		Scores:  [3]int{0, 50, 100},
+		Aliases: []*string{&alias},
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{StrictTags: true}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestRequireAtLeastOneRule(t *testing.T) {
+	opts := &ValidationOptions{RequireAtLeastOneRule: true}
+	_, _, err := ValidateE(&Test66{}, opts)
+	if err == nil {
+		t.Errorf("ValidateE did not return an error for struct without checked rules")
+	}
+
+	// without the option, such struct is valid
+	compare(&Test66{}, true, map[string]int64{}, &ValidationOptions{}, t)
+
+	// rules of nested structs are counted as well
+	s := Test8{Profile: &Test8Profile{FirstName: "John"}}
+	_, _, err = ValidateE(&s, &ValidationOptions{
+		RequireAtLeastOneRule: true,
+		RestrictFields: map[string]bool{
+			"Profile.FirstName": true,
+		},
+	})
+	if err != nil {
+		t.Errorf("ValidateE returned an error for struct with checked rules: %s", err)
+	}
+
+	// fields are counted by their rules, even when their values are not validated
+	optional := struct {
+		Name string `validation:"omitempty lenmin:3"`
+	}{}
+	nilPointer := struct {
+		Name *string `validation:"lenmin:3"`
+	}{}
+	for _, obj := range []interface{}{&optional, &nilPointer} {
+		valid, _, err := ValidateE(obj, opts)
+		if err != nil || !valid {
+			t.Errorf("ValidateE returned %v and %v for struct with rules which fields are not validated", valid, err)
+		}
+	}
+}
+
+func TestOperatorsWithInvalidValues(t *testing.T) {
+	s := Test67{
+		Count:    5,
+		Quantity: 4,
+		Ratio:    0,
+		Balance:  -1,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Count":    FailGt,
+		"Quantity": FailGt,
+		"Ratio":    FailGt,
+		"Balance":  FailGt,
+	}
+	opts := &ValidationOptions{}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s = Test67{
+		Count:    10,
+		Quantity: 11,
+		Ratio:    1.01,
+	}
+	expectedFailedFields = map[string]int64{
+		"Count":    FailLt,
+		"Quantity": FailLt,
+		"Ratio":    FailLt,
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestOperatorsWithValidValues(t *testing.T) {
+	s := Test67{
+		Count:    6,
+		Quantity: 5,
+		Ratio:    0.01,
+		Balance:  0,
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	opts := &ValidationOptions{StrictTags: true}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	s.Count = 9
+	s.Quantity = 10
+	s.Ratio = 1
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestAllowedValues(t *testing.T) {
+	s := Test61{
+		Status:   "draft",
+		Category: "news",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Category": FailOneOf,
+	}
+	opts := &ValidationOptions{
+		AllowedValues: map[string][]string{
+			"Category": {"sports", "weather"},
+		},
+	}
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+
+	_, messages := ValidateWithMessages(&s, opts)
+	expectedMessages := map[string][]string{
+		"Category": {"must be one of: sports, weather"},
+	}
+	if !reflect.DeepEqual(messages, expectedMessages) {
+		t.Errorf("ValidateWithMessages returned %v where it should be %v", messages, expectedMessages)
+	}
+
+	// list changes between calls, and value has to be in the list and in oneof from the tag
+	opts.AllowedValues["Category"] = []string{"news", "sports"}
+	compare(&s, true, map[string]int64{}, opts, t)
+
+	s.Category = "sports"
+	compare(&s, expectedBool, expectedFailedFields, opts, t)
+}
+
+func TestAllowedValuesWithInts(t *testing.T) {
+	s := Test63{Status: 201, Temperature: 20, Port: 80}
+	opts := &ValidationOptions{
+		AllowedValues: map[string][]string{
+			"Status": {"200", "204"},
+		},
+	}
+	compare(&s, false, map[string]int64{"Status": FailOneOf}, opts, t)
+
+	_, messages := ValidateWithMessages(&s, opts)
+	expectedMessages := map[string][]string{
+		"Status": {"must be one of: 200, 204"},
+	}
+	if !reflect.DeepEqual(messages, expectedMessages) {
+		t.Errorf("ValidateWithMessages returned %v where it should be %v", messages, expectedMessages)
+	}
+
+	opts.AllowedValues["Status"] = []string{"200", "201"}
+	compare(&s, true, map[string]int64{}, opts, t)
+}
+
+func TestCombinedLenMax(t *testing.T) {
+	name := "queue"
+	s := Test68{Namespace: "billing", Name: &name}
+	opts := &ValidationOptions{
+		CombinedLenMax: map[string]CombinedLen{
+			"Key": {Fields: []string{"Namespace", "Name"}, Max: 12},
+		},
+	}
+	compare(&s, true, map[string]int64{}, opts, t)
+
+	name = "queues"
+	compare(&s, false, map[string]int64{"Key": FailLenMax}, opts, t)
+
+	_, messages := ValidateWithMessages(&s, opts)
+	expectedMessages := map[string][]string{
+		"Key": {"must be at most 12 characters"},
+	}
+	if !reflect.DeepEqual(messages, expectedMessages) {
+		t.Errorf("ValidateWithMessages returned %v where it should be %v", messages, expectedMessages)
+	}
+
+	// nil pointer has zero length
+	s.Name = nil
+	compare(&s, true, map[string]int64{}, opts, t)
+}
+
+func TestCombinedLenMaxWithInvalidField(t *testing.T) {
+	s := Test68{Namespace: "billing"}
+	opts := &ValidationOptions{
+		CombinedLenMax: map[string]CombinedLen{
+			"Key": {Fields: []string{"Namespace", "Count"}, Max: 12},
+		},
+	}
+	_, _, err := ValidateE(&s, opts)
+	if err == nil {
+		t.Errorf("ValidateE should return an error when field is not a string")
+	}
+
+	opts.CombinedLenMax["Key"] = CombinedLen{Fields: []string{"Namespace", "Missing"}, Max: 12}
+	_, _, err = ValidateE(&s, opts)
+	if err == nil {
+		t.Errorf("ValidateE should return an error when field does not exist")
+	}
+}
+
+func TestSemVerWithInvalidValues(t *testing.T) {
+	s := Test69{
+		Version:    "1.2",
+		APIVersion: "v01.2.3",
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Version":    FailSemVer,
+		"APIVersion": FailSemVer,
+	}
+	compare(&s, expectedBool, expectedFailedFields, nil, t)
+
+	s = Test69{
+		Version:    "v1.2.3",
+		APIVersion: "1.2.3-rc..1",
+	}
+	compare(&s, expectedBool, expectedFailedFields, nil, t)
+
+	s = Test69{
+		Version:    "1.2.3-01",
+		APIVersion: "1.2.3+",
+	}
+	compare(&s, expectedBool, expectedFailedFields, nil, t)
+}
+
+func TestSemVerWithValidValues(t *testing.T) {
+	s := Test69{
+		Version:    "1.2.3",
+		APIVersion: "v1.2.3",
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	compare(&s, expectedBool, expectedFailedFields, nil, t)
+
+	s = Test69{
+		Version:    "1.2.3-rc.1+build",
+		APIVersion: "1.0.0-alpha-1.x-y-z.--+001.sha-5114f85",
+	}
+	compare(&s, expectedBool, expectedFailedFields, nil, t)
+
+	s.APIVersion = ""
+	compare(&s, expectedBool, expectedFailedFields, nil, t)
+}
+
+func TestValidateMap(t *testing.T) {
+	schema := map[string]string{
+		"name":   "req lenmin:3 lenmax:25",
+		"email":  "req email",
+		"age":    "req valmin:18 valmax:150",
+		"status": "ranges:200-299",
+		"active": "istrue",
+		"tags":   "lenmax:2",
+	}
+	var m map[string]interface{}
+	err := json.Unmarshal([]byte(`{"name": "Jane", "email": "jane@example.com", "age": 30, "status": 204, "active": true, "tags": ["a"]}`), &m)
+	if err != nil {
+		t.Fatalf("json.Unmarshal failed: %s", err.Error())
+	}
+
+	valid, failedFields := ValidateMap(m, schema, nil)
+	if !valid || !reflect.DeepEqual(failedFields, map[string]int64{}) {
+		t.Errorf("ValidateMap returned %v and %v where it should be true and an empty map", valid, failedFields)
+	}
+
+	m["status"] = float64(404)
+	m["tags"] = []interface{}{"a", "b", "c"}
+	m["age"] = float64(17)
+	expectedFailedFields := map[string]int64{
+		"status": FailRanges,
+		"tags":   FailLenMax,
+		"age":    FailValMin,
+	}
+	valid, failedFields = ValidateMap(m, schema, nil)
+	if valid || !reflect.DeepEqual(failedFields, expectedFailedFields) {
+		t.Errorf("ValidateMap returned %v where it should be %v", failedFields, expectedFailedFields)
+	}
+}
+
+func TestValidateMapWithMissingKeys(t *testing.T) {
+	schema := map[string]string{
+		"name":  "req lenmin:3",
+		"email": "req email",
+		"phone": "lenmin:9",
+	}
+	m := map[string]interface{}{
+		"email": nil,
+	}
+	expectedFailedFields := map[string]int64{
+		"name":  FailEmpty,
+		"email": FailNil,
+	}
+	valid, failedFields := ValidateMap(m, schema, nil)
+	if valid || !reflect.DeepEqual(failedFields, expectedFailedFields) {
+		t.Errorf("ValidateMap returned %v where it should be %v", failedFields, expectedFailedFields)
+	}
+}
+
+func TestValidateMapWithWrongTypes(t *testing.T) {
+	schema := map[string]string{
+		"email":  "req email",
+		"age":    "req valmin:18",
+		"active": "istrue",
+	}
+	m := map[string]interface{}{
+		"email":  float64(5),
+		"age":    "30",
+		"active": "true",
+	}
+	expectedFailedFields := map[string]int64{
+		"email":  FailType,
+		"age":    FailType,
+		"active": FailType,
+	}
+	valid, failedFields := ValidateMap(m, schema, nil)
+	if valid || !reflect.DeepEqual(failedFields, expectedFailedFields) {
+		t.Errorf("ValidateMap returned %v where it should be %v", failedFields, expectedFailedFields)
+	}
+}
+
+func TestValidateMapWithNumbersForIntRules(t *testing.T) {
+	schema := map[string]string{
+		"size":   "oneofint:1,2,3",
+		"status": "ranges:200-299",
+		"pin":    "digits:4",
+		"count":  "oneofint:1,2,3",
+	}
+	m := map[string]interface{}{
+		"size":   2.5,
+		"status": 250.5,
+		"pin":    12.5,
+		"count":  float64(2),
+	}
+	expectedFailedFields := map[string]int64{
+		"size":   FailType,
+		"status": FailType,
+		"pin":    FailType,
+	}
+	valid, failedFields := ValidateMap(m, schema, nil)
+	if valid || !reflect.DeepEqual(failedFields, expectedFailedFields) {
+		t.Errorf("ValidateMap returned %v where it should be %v", failedFields, expectedFailedFields)
+	}
+}
+
+func TestValidateMapWithOptions(t *testing.T) {
+	schema := map[string]string{
+		"name":  "req",
+		"score": "omitempty valmin:10",
+	}
+	m := map[string]interface{}{
+		"name":  "N/A",
+		"score": float64(-1),
+	}
+	expectedFailedFields := map[string]int64{
+		"name": FailEmpty,
+	}
+	valid, failedFields := ValidateMap(m, schema, &ValidationOptions{
+		EmptyValues:      map[reflect.Kind][]interface{}{reflect.String: {"N/A"}},
+		EmptyFieldValues: map[string][]interface{}{"score": {float64(-1)}},
+	})
+	if valid || !reflect.DeepEqual(failedFields, expectedFailedFields) {
+		t.Errorf("ValidateMap returned %v where it should be %v", failedFields, expectedFailedFields)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("ValidateMap did not panic for unknown rule with StrictTags")
+		}
+	}()
+	ValidateMap(m, map[string]string{"name": "req lenmn:3"}, &ValidationOptions{StrictTags: true})
+}
+
+func TestPercentWithInvalidValues(t *testing.T) {
+	s := Test70{
+		Discount: -1,
+		Progress: 100.5,
+		Share:    101,
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Discount": FailValMin,
+		"Progress": FailValMax,
+		"Share":    FailValMax,
+	}
+	compare(&s, expectedBool, expectedFailedFields, nil, t)
+
+	s = Test70{
+		Discount: 101,
+		Progress: -0.1,
+		Share:    200,
+	}
+	expectedFailedFields = map[string]int64{
+		"Discount": FailValMax,
+		"Progress": FailValMin,
+		"Share":    FailValMax,
+	}
+	compare(&s, expectedBool, expectedFailedFields, nil, t)
+}
+
+func TestPercentWithValidValues(t *testing.T) {
+	s := Test70{
+		Discount: 0,
+		Progress: 0,
+		Share:    0,
+	}
+	expectedBool := true
+	expectedFailedFields := map[string]int64{}
+	compare(&s, expectedBool, expectedFailedFields, nil, t)
+
+	s = Test70{
+		Discount: 100,
+		Progress: 99.9,
+		Share:    100,
+	}
+	compare(&s, expectedBool, expectedFailedFields, nil, t)
+}
+
+func TestPercentFloatWithInvalidType(t *testing.T) {
+	type Test struct {
+		Score    int      `validation:"percentfloat"`
+		Ratio    float32  `validation:"percentfloat"`
+		Progress *float64 `validation:"percentfloat"`
+	}
+	progress := 50.5
+	s := Test{
+		Score:    50,
+		Ratio:    100,
+		Progress: &progress,
+	}
+	expectedFailedFields := map[string]int64{
+		"Score": FailType,
+	}
+	compare(&s, false, expectedFailedFields, nil, t)
+
+	schema := map[string]string{"progress": "percentfloat"}
+	valid, failedFields := ValidateMap(map[string]interface{}{"progress": "50"}, schema, nil)
+	if valid || !reflect.DeepEqual(failedFields, map[string]int64{"progress": FailType}) {
+		t.Errorf("ValidateMap returned %v where it should be FailType for string with percentfloat", failedFields)
+	}
+	valid, failedFields = ValidateMap(map[string]interface{}{"progress": float64(99.5)}, schema, nil)
+	if !valid || len(failedFields) != 0 {
+		t.Errorf("ValidateMap returned %v where it should be valid for float with percentfloat", failedFields)
+	}
+}
+
+func TestValidateEmbeddedInterfaces(t *testing.T) {
+	s := Test71{
+		Test71Plugin: Test71Impl{Name: "ab", Version: "1.2"},
+		ID:           "x",
+	}
+	opts := &ValidationOptions{ValidateEmbeddedInterfaces: true}
+	expectedFailedFields := map[string]int64{
+		"Test71Plugin.Name":    FailLenMin,
+		"Test71Plugin.Version": FailSemVer,
+	}
+	compare(&s, false, expectedFailedFields, opts, t)
+
+	// pointer to a struct is dereferenced
+	s.Test71Plugin = &Test71Impl{Name: "abc", Version: "1.2.3"}
+	compare(&s, true, map[string]int64{}, opts, t)
+
+	// without the option, struct held by the interface is not validated
+	s.Test71Plugin = Test71Impl{}
+	compare(&s, true, map[string]int64{}, nil, t)
+}
+
+func TestValidateEmbeddedInterfacesWithNil(t *testing.T) {
+	s := Test71{ID: "x"}
+	opts := &ValidationOptions{ValidateEmbeddedInterfaces: true}
+	compare(&s, false, map[string]int64{"Test71Plugin": FailNil}, opts, t)
+
+	var impl *Test71Impl
+	s.Test71Plugin = impl
+	compare(&s, false, map[string]int64{"Test71Plugin": FailNil}, opts, t)
+}
+
+func TestValidateWithCycles(t *testing.T) {
+	type Node struct {
+		Name     string `validation:"req lenmin:3"`
+		Prev     *Node
+		Next     *Node
+		Children []*Node
+	}
+	a := &Node{Name: "first"}
+	b := &Node{Name: "b"}
+	a.Next = b
+	b.Prev = a
+	a.Children = []*Node{a, b}
+
+	valid, failedFields := Validate(a, nil)
+	if valid {
+		t.Fatalf("Validate returned invalid boolean value for struct with cycles")
+	}
+	expectedFailedFields := map[string]int{
+		"Next.Name":        FailLenMin,
+		"Children[1].Name": FailLenMin,
+	}
+	if !reflect.DeepEqual(failedFields, expectedFailedFields) {
+		t.Fatalf("Validate returned %v where it should be %v", failedFields, expectedFailedFields)
+	}
+}
+
+func TestValidateNestedStructValue(t *testing.T) {
+	type Test struct {
+		Name     string `validation:"req"`
+		Profile  Test8Profile
+		Created  time.Time `validation:"req"`
+		Nickname sql.NullString
+	}
+	s := Test{
+		Name:    "John",
+		Profile: Test8Profile{FirstName: "J", Age: 20},
+		Created: time.Now(),
+	}
+	expectedBool := false
+	expectedFailedFields := map[string]int64{
+		"Profile.FirstName": FailLenMin,
+	}
+	compare(&s, expectedBool, expectedFailedFields, nil, t)
+
+	// as with pointers, nested fields are validated when the struct field is restricted
+	compare(&s, expectedBool, expectedFailedFields, &ValidationOptions{RestrictFields: map[string]bool{"Profile": true}}, t)
+	compare(&s, true, map[string]int64{}, &ValidationOptions{RestrictFields: map[string]bool{"Name": true}}, t)
+}
+
+func TestFormatRulesWithEmptyString(t *testing.T) {
+	tags := []string{"url", "uuid", "uuid:v4", "ip", "ip:v4", "ip:v6", "cidr", "base64", "base64url", "hex", "hostname", "fqdn", "semver", "semver:v", "creditcard", "json", "datetime:2006-01-02", "decimal:2", "numericrange", "charset:abc"}
+	for _, tag := range tags {
+		if valid, failureFlags := ValidateField("", tag, ""); !valid {
+			t.Errorf("ValidateField returned %d for empty string with '%s' where it should be valid", failureFlags, tag)
+		}
+		if valid, failureFlags := ValidateField("", "req "+tag, ""); valid || failureFlags != FailEmpty {
+			t.Errorf("ValidateField returned %d for empty string with 'req %s' where it should be FailEmpty", failureFlags, tag)
+		}
+	}
+
+	type Test struct {
+		DBHost string
+	}
+	compare(&Test{}, true, map[string]int64{}, &ValidationOptions{ValidateWhenSuffix: true}, t)
+}
+
+func BenchmarkValidateEmail(b *testing.B) {
+	s := Test1{
+		Email: "john@example.com",
+	}
+	opts := &ValidationOptions{
+		RestrictFields: map[string]bool{
+			"Email": true,
+		},
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Validate(&s, opts)
+	}
+}
+
+func BenchmarkValidateRegexp(b *testing.B) {
+	s := Test1{
+		PostCode: "43-155",
+		Country:  "GB",
+	}
+	opts := &ValidationOptions{
+		RestrictFields: map[string]bool{
+			"PostCode": true,
+			"Country":  true,
+		},
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Validate(&s, opts)
+	}
+}
+
+func BenchmarkValidateCold(b *testing.B) {
+	s := Test1{}
+	opts := &ValidationOptions{}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		defaultCache.fields.Range(func(key, value interface{}) bool {
+			defaultCache.fields.Delete(key)
+			return true
+		})
+		Validate(&s, opts)
+	}
+}
+
+func BenchmarkValidateWarm(b *testing.B) {
+	s := Test1{}
+	opts := &ValidationOptions{}
+	Validate(&s, opts)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Validate(&s, opts)
+	}
+}
+
+func BenchmarkValidateParallel(b *testing.B) {
+	s := Test1{}
+	opts := &ValidationOptions{}
+	Validate(&s, opts)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			Validate(&s, opts)
+		}
+	})
+}
+
+func TestValidateConcurrently(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s := Test1{}
+			valid, failedFields := Validate(&s, &ValidationOptions{})
+			if valid || len(failedFields) != 7 {
+				t.Errorf("Validate returned invalid result when called concurrently")
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestValidateConcurrentlyWithSharedCaches(t *testing.T) {
+	validator := NewValidator(ValidationOptions{ValidateWhenSuffix: true})
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			// different struct types and options are parsed and cached at the same time
+			s := Test47{
+				Items: []Test47Item{{Name: "Apple"}},
+			}
+			valid, failedFields := Validate(&s, &ValidationOptions{FailFast: i%2 == 0})
+			if valid || failedFields["Items[0].Quantity"] != FailValMin {
+				t.Errorf("Validate returned invalid result when called concurrently")
+			}
+
+			s1 := Test1{}
+			valid, failedFields = validator.Validate(&s1)
+			if valid || len(failedFields) != 7 {
+				t.Errorf("Validator returned invalid result when called concurrently")
+			}
+
+			RegisterValidator("concurrent", func(value reflect.Value) (bool, int64) {
+				return true, 0
+			})
+			ok, _ := ValidateField(i, "custom:concurrent valmin:0", "")
+			if !ok {
+				t.Errorf("ValidateField returned invalid result when called concurrently")
+			}
+
+			_, messages := ValidateWithMessages(&Test45{Meta: map[string]string{"BAD": "x"}}, nil)
+			if len(messages["Meta[BAD]"]) != 1 {
+				t.Errorf("ValidateWithMessages returned invalid result when called concurrently")
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func compare(s interface{}, expectedBool bool, expectedFailedFields map[string]int64, options *ValidationOptions, t *testing.T) {
+	valid, failedFields := ValidateFlags64(s, options)
 	if valid != expectedBool {
 		t.Fatalf("Validate returned invalid boolean value")
 	}
 	compareFailedFields(failedFields, expectedFailedFields, t)
 }
 
-func compareFailedFields(failedFields map[string]int, expectedFailedFields map[string]int, t *testing.T) {
+func compareFailedFields(failedFields map[string]int64, expectedFailedFields map[string]int64, t *testing.T) {
 	if len(failedFields) != len(expectedFailedFields) {
 		for k, v := range failedFields {
 			log.Printf("%s %d", k, v)
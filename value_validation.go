@@ -1,19 +1,135 @@
 package structvalidator
 
 import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"net"
+	"net/url"
 	"reflect"
 	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
 )
 
 type ValueValidation struct {
 	LenMin int
 	LenMax int
-	ValMin int64
-	ValMax int64
-	Regexp *regexp.Regexp
-	Flags  int64
+	Len    int
+	// DigitsMin, DigitsMax and Digits are bounds for the number of decimal digits of an int field, -1 when not set
+	DigitsMin int
+	DigitsMax int
+	Digits    int
+	// Decimal is the maximal number of fractional digits of a decimal number in a string, -1 when not set
+	Decimal int
+	ValMin  int64
+	ValMax  int64
+	// ValMinFloat and ValMaxFloat are bounds used for float fields
+	ValMinFloat float64
+	ValMaxFloat float64
+	// ValMinUint and ValMaxUint are bounds used for unsigned int fields
+	ValMinUint uint64
+	ValMaxUint uint64
+	// Ranges contains minimum and maximum of each of the ranges that an int must be in, parsed from ranges tag
+	Ranges [][2]int64
+	// Gt and Lt are bounds of gt, gte, lt and lte rules, which are set with Gt, Gte, Lt and Lte flags
+	Gt float64
+	Lt float64
+	// MultipleOf is a number that an int or a float has to be a multiple of, 0 when not set
+	MultipleOf float64
+	Regexp     *regexp.Regexp
+	RegexpNot  *regexp.Regexp
+	OneOf      []string
+	// OneOfInt contains allowed values of an int field, parsed from oneofint tag
+	OneOfInt []int64
+	Prefix   string
+	Suffix   string
+	Contains []string
+	Excludes []string
+	// Charset contains characters that a string can consist of, and CharsetNot the ones it cannot contain
+	Charset    string
+	CharsetNot string
+	// DateTime is a layout of time.Parse that a string must match, eg. "2006-01-02"
+	DateTime string
+	// EqField is a name of another struct field which value must be equal, it is checked in Validate
+	EqField string
+	// GtField, GteField, LtField and LteField are names of other numeric struct fields which value is compared with,
+	// they are checked in Validate
+	GtField  string
+	GteField string
+	LtField  string
+	LteField string
+	// WithinField is a name of another numeric or time.Time struct field, and the difference between the values cannot
+	// be greater than WithinDelta (in seconds for time.Time), it is checked in Validate
+	WithinField string
+	WithinDelta float64
+	// RequiredWith contains names of struct fields, and when any of them is not zero then the field is required
+	RequiredWith []string
+	// RequiredWithout contains names of struct fields, and when all of them are zero then the field is required
+	RequiredWithout []string
+	// Custom contains names of custom validators, they are run in Validate
+	Custom []string
+	// Keys and Values are validations of map keys and values, they are checked in Validate
+	Keys   *ValueValidation
+	Values *ValueValidation
+	// Elements is validation of each element of a slice or an array, it is checked in Validate
+	Elements *ValueValidation
+	// Message is returned by ValidateWithMessages, instead of the generated messages, when any rule fails
+	Message string
+	// EmailRegexp is used instead of the global email regular expression when Email flag is set
+	EmailRegexp *regexp.Regexp
+	Flags       int64
 }
 
+// emailRegex is compiled once and used to validate fields with Email flag
+var emailRegex = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+\\/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
+
+// customEmailRegex is set with SetEmailRegexp and replaces emailRegex when not nil
+var customEmailRegex atomic.Pointer[regexp.Regexp]
+
+// SetEmailRegexp sets regular expression used globally to validate fields with Email flag, instead of the built-in
+// one.  EmailRegexp in ValidationOptions takes precedence over it.  Passing nil restores the built-in one.
+func SetEmailRegexp(re *regexp.Regexp) {
+	customEmailRegex.Store(re)
+}
+
+// getEmailRegexp returns regular expression used to validate fields with Email flag
+func (v *ValueValidation) getEmailRegexp() *regexp.Regexp {
+	if v.EmailRegexp != nil {
+		return v.EmailRegexp
+	}
+	if re := customEmailRegex.Load(); re != nil {
+		return re
+	}
+	return emailRegex
+}
+
+// uuidRegex and uuidV4Regex are used to validate fields with UUID and UUIDv4 flags
+var uuidRegex = regexp.MustCompile("^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$")
+var uuidV4Regex = regexp.MustCompile("^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-4[0-9a-fA-F]{3}-[89abAB][0-9a-fA-F]{3}-[0-9a-fA-F]{12}$")
+
+// hostnameRegex and fqdnRegex are used to validate fields with Hostname and FQDN flags.  Each label has 1 to 63
+// letters, digits and hyphens, and it cannot start or end with a hyphen (RFC 1123).  FQDN has at least two labels
+// and it can end with a dot, while hostname cannot.
+var hostnameRegex = regexp.MustCompile("^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(\\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
+var fqdnRegex = regexp.MustCompile("^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(\\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)+\\.?$")
+
+// semverRegex is used to validate fields with SemVer flag, against SemVer 2.0 grammar.  Numbers cannot have leading
+// zeros, and pre-release and build metadata are dot-separated identifiers of letters, digits and hyphens.
+var semverRegex = regexp.MustCompile("^(0|[1-9][0-9]*)\\.(0|[1-9][0-9]*)\\.(0|[1-9][0-9]*)" +
+	"(-(0|[1-9][0-9]*|[0-9]*[a-zA-Z-][0-9a-zA-Z-]*)(\\.(0|[1-9][0-9]*|[0-9]*[a-zA-Z-][0-9a-zA-Z-]*))*)?" +
+	"(\\+[0-9a-zA-Z-]+(\\.[0-9a-zA-Z-]+)*)?$")
+
+// alphaRegex, numericRegex and alphanumericRegex are used to validate fields with Alpha, Numeric and Alphanumeric
+// flags.  Only ASCII letters and digits are allowed.
+var alphaRegex = regexp.MustCompile("^[a-zA-Z]+$")
+var numericRegex = regexp.MustCompile("^[0-9]+$")
+var alphanumericRegex = regexp.MustCompile("^[a-zA-Z0-9]+$")
+
 // values used with flags
 const (
 	_            = iota
@@ -21,9 +137,62 @@ const (
 	ValMaxNotNil
 	Required
 	Email
+	IsTrue
+	IsFalse
+	URL
+	UUID
+	UUIDv4
+	Trim
+	Alpha
+	Numeric
+	Alphanumeric
+	ReqTrim
+	TimeAfterNow
+	TimeBeforeNow
+	Lowercase
+	Uppercase
+	NumericRange
+	IP
+	IPv4
+	IPv6
+	CIDR
+	Base64
+	Base64URL
+	Hex
+	NotBlank
+	OmitEmpty
+	JSON
+	Positive
+	NonNegative
+	Negative
+	NonPositive
+	RuneLen
+	CreditCard
+	Hostname
+	FQDN
+	OneOfCI
+	Gt
+	Gte
+	Lt
+	Lte
+	SemVer
+	SemVerPrefix
+	PercentFloat
 )
 
+// ValidateReflectValue validates value against the rules.  A failing required rule is returned immediately, as other
+// rules are moot in such case, eg. empty string with "req lenmin:3" fails with FailEmpty only.  Otherwise all the
+// rules are evaluated and the returned failureFlags is a bitwise OR of all the Fail* constants for the rules that
+// failed, and a satisfied required rule does not add any flag.
 func (v *ValueValidation) ValidateReflectValue(value reflect.Value) (ok bool, failureFlags int) {
+	f := v.validate(value)
+	return len(f) == 0, int(f.flags())
+}
+
+// validate works the same as ValidateReflectValue but returns the rules that failed, each with its name
+func (v *ValueValidation) validate(value reflect.Value) failures {
+	var f failures
+
 	minCanBeZero := false
 	maxCanBeZero := false
 	if v.Flags&ValMinNotNil > 0 {
@@ -33,52 +202,620 @@ func (v *ValueValidation) ValidateReflectValue(value reflect.Value) (ok bool, fa
 		maxCanBeZero = true
 	}
 
+	// with trim, all the string rules are checked against the value without leading and trailing whitespace.  The
+	// trimmed value is not written back to the struct field.
+	if v.Flags&Trim > 0 && value.Kind() == reflect.String {
+		value = reflect.ValueOf(strings.TrimSpace(value.String()))
+	}
+
+	// required bool must be true, the same as a required checkbox in HTML form must be checked
+	if value.Kind() == reflect.Bool {
+		if v.Flags&IsTrue > 0 && !value.Bool() {
+			return failures{{flag: FailBool, name: "istrue"}}
+		}
+		if v.Flags&Required > 0 && !value.Bool() {
+			return failures{{flag: FailBool, name: v.getRequiredRule()}}
+		}
+		if v.Flags&IsFalse > 0 && value.Bool() {
+			return failures{{flag: FailBool, name: "isfalse"}}
+		}
+	}
+
+	// time.Time is validated with its own rules only, as other ones do not apply to it.  It works with exported fields
+	// only, unless ValidateUnexported option is set.
+	if isTime(value.Type()) {
+		if !value.CanInterface() {
+			return nil
+		}
+		t := value.Interface().(time.Time)
+		if v.Flags&Required > 0 && t.IsZero() {
+			return failures{{flag: FailTime, name: v.getRequiredRule()}}
+		}
+		if v.Flags&TimeAfterNow > 0 && !t.After(time.Now()) {
+			f.add(FailTime, "after:now")
+		}
+		if v.Flags&TimeBeforeNow > 0 && !t.Before(time.Now()) {
+			f.add(FailTime, "before:now")
+		}
+		return f
+	}
+
 	if v.Flags&Required > 0 {
-		if value.Type().Name() == "string" && value.String() == "" {
-			return false, FailEmpty
+		if value.Kind() == reflect.String && value.String() == "" {
+			return failures{{flag: FailEmpty, name: v.getRequiredRule()}}
+		}
+		if value.Kind() == reflect.String && v.Flags&ReqTrim > 0 && strings.TrimSpace(value.String()) == "" {
+			return failures{{flag: FailEmpty, name: v.getRequiredRule()}}
+		}
+		if (value.Kind() == reflect.Slice || value.Kind() == reflect.Array || value.Kind() == reflect.Map) && value.Len() == 0 {
+			return failures{{flag: FailEmpty, name: v.getRequiredRule()}}
 		}
-		if isInt(value.Kind()) && value.Int() == 0 && !minCanBeZero && !maxCanBeZero && v.ValMin == 0 && v.ValMax == 0 {
-			return false, FailZero
+		// slices and maps are empty when nil, while channels and funcs can only be nil
+		if (value.Kind() == reflect.Chan || value.Kind() == reflect.Func) && value.IsNil() {
+			return failures{{flag: FailNil, name: v.getRequiredRule()}}
 		}
+		// for numbers, req rejects zero unless valmin or valmax is set, eg. "req" rejects 0 while "req valmin:0" or
+		// "req valmin:-5 valmax:5" accepts it.  When there is a range, it decides whether zero is valid.
+		if (isInt(value.Kind()) || isFloat(value.Kind())) && value.IsZero() && !v.hasRange(value.Kind()) {
+			return failures{{flag: FailZero, name: v.getRequiredRule()}}
+		}
+	}
+
+	// percentfloat applies to floats only, so other kinds fail with FailType, eg. an int field where percent is meant
+	if v.Flags&PercentFloat > 0 && !isFloat(value.Kind()) {
+		return failures{{flag: FailType, name: "percentfloat"}}
 	}
 
-	if value.Type().Name() == "string" {
-		if v.LenMin > 0 && len(value.String()) < v.LenMin {
-			return false, FailLenMin
+	if value.Kind() == reflect.String {
+		// length is a number of bytes, or a number of characters with runelen
+		length := len(value.String())
+		if v.Flags&RuneLen > 0 {
+			length = utf8.RuneCountInString(value.String())
 		}
-		if v.LenMax > 0 && len(value.String()) > v.LenMax {
-			return false, FailLenMax
+		if v.LenMin > 0 && length < v.LenMin {
+			f.add(FailLenMin, "lenmin")
+		}
+		if v.LenMax > 0 && length > v.LenMax {
+			f.add(FailLenMax, "lenmax")
+		}
+		if v.Len > -1 && length != v.Len {
+			f.add(FailLen, "len")
 		}
 
 		if v.Regexp != nil {
 			if !v.Regexp.MatchString(value.String()) {
-				return false, FailRegexp
+				f.add(FailRegexp, "regexp")
 			}
 		}
+		if v.RegexpNot != nil && v.RegexpNot.MatchString(value.String()) {
+			f.add(FailRegexpNot, "regexpnot")
+		}
 
 		if v.Flags&Email > 0 {
-			var emailRegex = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+\\/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
-			if !emailRegex.MatchString(value.String()) {
-				return false, FailEmail
+			if !v.getEmailRegexp().MatchString(value.String()) {
+				f.add(FailEmail, "email")
 			}
 		}
+
+		// format rules, eg. ip or json, do not check empty string, as it is handled by req
+		if value.String() != "" {
+			if v.Flags&URL > 0 && !isURL(value.String()) {
+				f.add(FailURL, "url")
+			}
+
+			if v.Flags&UUID > 0 && !uuidRegex.MatchString(value.String()) {
+				f.add(FailUUID, "uuid")
+			}
+			if v.Flags&UUIDv4 > 0 && !uuidV4Regex.MatchString(value.String()) {
+				f.add(FailUUID, "uuid:v4")
+			}
+
+			if v.Flags&IP > 0 && !v.isIP(value.String()) {
+				f.add(FailIP, "ip")
+			}
+			if v.Flags&IPv4 > 0 && !v.isIP(value.String()) {
+				f.add(FailIP, "ip:v4")
+			}
+			if v.Flags&IPv6 > 0 && !v.isIP(value.String()) {
+				f.add(FailIP, "ip:v6")
+			}
+			if v.Flags&CIDR > 0 && !isCIDR(value.String()) {
+				f.add(FailCIDR, "cidr")
+			}
+
+			if v.Flags&Base64 > 0 && !isBase64(value.String(), base64.StdEncoding) {
+				f.add(FailBase64, "base64")
+			}
+			if v.Flags&Base64URL > 0 && !isBase64(value.String(), base64.URLEncoding) {
+				f.add(FailBase64, "base64url")
+			}
+			if v.Flags&Hex > 0 && !isHex(value.String()) {
+				f.add(FailHex, "hex")
+			}
+
+			// whole name cannot be longer than 253 characters, without the trailing dot
+			if v.Flags&Hostname > 0 && (len(value.String()) > 253 || !hostnameRegex.MatchString(value.String())) {
+				f.add(FailHostname, "hostname")
+			}
+			if v.Flags&FQDN > 0 && (len(strings.TrimSuffix(value.String(), ".")) > 253 || !fqdnRegex.MatchString(value.String())) {
+				f.add(FailFQDN, "fqdn")
+			}
+
+			if v.Flags&SemVer > 0 && !isSemVer(value.String(), v.Flags&SemVerPrefix > 0) {
+				if v.Flags&SemVerPrefix > 0 {
+					f.add(FailSemVer, "semver:v")
+				} else {
+					f.add(FailSemVer, "semver")
+				}
+			}
+
+			if v.Flags&CreditCard > 0 && !isCreditCard(value.String()) {
+				f.add(FailCreditCard, "creditcard")
+			}
+
+			if v.Flags&JSON > 0 && !json.Valid([]byte(value.String())) {
+				f.add(FailJSON, "json")
+			}
+
+			if v.DateTime != "" && !isDateTime(value.String(), v.DateTime) {
+				f.add(FailDateTime, "datetime")
+			}
+
+			if v.Decimal > -1 && !isDecimal(value.String(), v.Decimal) {
+				f.add(FailDecimal, "decimal")
+			}
+
+			// with numericrange, valmin and valmax apply to the number in string, eg. json.Number.  "NaN" and "Inf" are
+			// parsed by strconv but they are not valid numbers, and NaN would pass any bound.
+			if v.Flags&NumericRange > 0 {
+				n, err := strconv.ParseFloat(value.String(), 64)
+				if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+					f.add(FailNumber, "numericrange")
+				} else {
+					if (v.ValMinFloat != 0 || minCanBeZero) && v.ValMinFloat > n {
+						f.add(FailValMin, "valmin")
+					}
+					if (v.ValMaxFloat != 0 || maxCanBeZero) && v.ValMaxFloat < n {
+						f.add(FailValMax, "valmax")
+					}
+				}
+			}
+
+			if v.Charset != "" && strings.IndexFunc(value.String(), func(r rune) bool { return !strings.ContainsRune(v.Charset, r) }) > -1 {
+				f.add(FailCharset, "charset")
+			}
+		}
+
+		if v.Flags&Alpha > 0 && !alphaRegex.MatchString(value.String()) {
+			f.add(FailAlpha, "alpha")
+		}
+		if v.Flags&Numeric > 0 && !numericRegex.MatchString(value.String()) {
+			f.add(FailNumeric, "numeric")
+		}
+		if v.Flags&Alphanumeric > 0 && !alphanumericRegex.MatchString(value.String()) {
+			f.add(FailAlphanumeric, "alphanumeric")
+		}
+
+		if v.Prefix != "" && !strings.HasPrefix(value.String(), v.Prefix) {
+			f.add(FailPrefix, "prefix")
+		}
+		if v.Suffix != "" && !strings.HasSuffix(value.String(), v.Suffix) {
+			f.add(FailSuffix, "suffix")
+		}
+
+		for _, substr := range v.Contains {
+			if !strings.Contains(value.String(), substr) {
+				f.add(FailContains, "contains")
+			}
+		}
+		for _, substr := range v.Excludes {
+			if strings.Contains(value.String(), substr) {
+				f.add(FailExcludes, "excludes")
+			}
+		}
+
+		if v.CharsetNot != "" && strings.ContainsAny(value.String(), v.CharsetNot) {
+			f.add(FailCharsetNot, "charsetnot")
+		}
+
+		// strings without cased characters, eg. "123", are both lowercase and uppercase
+		if v.Flags&Lowercase > 0 && value.String() != strings.ToLower(value.String()) {
+			f.add(FailLowercase, "lowercase")
+		}
+		if v.Flags&Uppercase > 0 && value.String() != strings.ToUpper(value.String()) {
+			f.add(FailUppercase, "uppercase")
+		}
 	}
 
-	if isInt(value.Kind()) {
+	// for slices, arrays and maps, length rules apply to the number of elements
+	if value.Kind() == reflect.Slice || value.Kind() == reflect.Array || value.Kind() == reflect.Map {
+		if v.LenMin > 0 && value.Len() < v.LenMin {
+			f.addRule(failedRule{flag: FailLenMin, name: "lenmin", elements: true})
+		}
+		if v.LenMax > 0 && value.Len() > v.LenMax {
+			f.addRule(failedRule{flag: FailLenMax, name: "lenmax", elements: true})
+		}
+		if v.Len > -1 && value.Len() != v.Len {
+			f.addRule(failedRule{flag: FailLen, name: "len", elements: true})
+		}
+	}
+
+	if isSignedInt(value.Kind()) {
 		if (v.ValMin != 0 || minCanBeZero) && v.ValMin > value.Int() {
-			return false, FailValMin
+			f.add(FailValMin, "valmin")
 		}
 		if (v.ValMax != 0 || maxCanBeZero) && v.ValMax < value.Int() {
-			return false, FailValMax
+			f.add(FailValMax, "valmax")
+		}
+	}
+
+	// negative minimum is always met by an unsigned value, and negative maximum can never be met
+	// ValMinUint and ValMaxUint are used when set, as they can hold bounds greater than math.MaxInt64
+	if isUint(value.Kind()) {
+		valMin := v.ValMinUint
+		if valMin == 0 && v.ValMin > 0 {
+			valMin = uint64(v.ValMin)
+		}
+		valMax := v.ValMaxUint
+		if valMax == 0 && v.ValMax > 0 {
+			valMax = uint64(v.ValMax)
+		}
+
+		if valMin > value.Uint() {
+			f.add(FailValMin, "valmin")
+		}
+		if v.ValMax < 0 || ((valMax != 0 || maxCanBeZero) && valMax < value.Uint()) {
+			f.add(FailValMax, "valmax")
+		}
+	}
+
+	// number of digits is counted for the absolute value, so minus sign is not a digit
+	if isInt(value.Kind()) && (v.DigitsMin > -1 || v.DigitsMax > -1 || v.Digits > -1) {
+		var digits int
+		if isSignedInt(value.Kind()) {
+			digits = len(strings.TrimPrefix(strconv.FormatInt(value.Int(), 10), "-"))
+		} else {
+			digits = len(strconv.FormatUint(value.Uint(), 10))
+		}
+		if v.DigitsMin > -1 && digits < v.DigitsMin {
+			f.add(FailDigits, "digitsmin")
+		}
+		if v.DigitsMax > -1 && digits > v.DigitsMax {
+			f.add(FailDigits, "digitsmax")
+		}
+		if v.Digits > -1 && digits != v.Digits {
+			f.add(FailDigits, "digits")
 		}
 	}
 
-	return true, 0
+	if isFloat(value.Kind()) {
+		if (v.ValMinFloat != 0 || minCanBeZero) && v.ValMinFloat > value.Float() {
+			f.add(FailValMin, "valmin")
+		}
+		if (v.ValMaxFloat != 0 || maxCanBeZero) && v.ValMaxFloat < value.Float() {
+			f.add(FailValMax, "valmax")
+		}
+	}
+
+	// sign rules do not use bounds so they do not conflict with valmin and valmax
+	if isInt(value.Kind()) || isFloat(value.Kind()) {
+		sign := getSign(value)
+		if v.Flags&Positive > 0 && sign <= 0 {
+			f.add(FailValMin, "positive")
+		}
+		if v.Flags&NonNegative > 0 && sign < 0 {
+			f.add(FailValMin, "nonneg")
+		}
+		if v.Flags&Negative > 0 && sign >= 0 {
+			f.add(FailValMax, "negative")
+		}
+		if v.Flags&NonPositive > 0 && sign > 0 {
+			f.add(FailValMax, "nonpositive")
+		}
+	}
+
+	// gt and lt are exclusive, while gte and lte are inclusive
+	if isInt(value.Kind()) || isFloat(value.Kind()) {
+		n := toFloat(value)
+		if v.Flags&Gt > 0 && n <= v.Gt {
+			f.add(FailGt, "gt")
+		}
+		if v.Flags&Gte > 0 && n < v.Gt {
+			f.add(FailGt, "gte")
+		}
+		if v.Flags&Lt > 0 && n >= v.Lt {
+			f.add(FailLt, "lt")
+		}
+		if v.Flags&Lte > 0 && n > v.Lt {
+			f.add(FailLt, "lte")
+		}
+	}
+
+	if len(v.Ranges) > 0 && isInt(value.Kind()) && !v.isInRanges(value) {
+		f.add(FailRanges, "ranges")
+	}
+
+	if v.MultipleOf != 0 && (isInt(value.Kind()) || isFloat(value.Kind())) && !isMultipleOf(value, v.MultipleOf) {
+		f.add(FailMultipleOf, "multipleof")
+	}
+
+	if len(v.OneOf) > 0 && !v.isOneOf(value) {
+		if v.Flags&OneOfCI > 0 {
+			f.add(FailOneOf, "oneofci")
+		} else {
+			f.add(FailOneOf, "oneof")
+		}
+	}
+	if len(v.OneOfInt) > 0 && isInt(value.Kind()) && !v.isOneOfInt(value) {
+		f.add(FailOneOf, "oneofint")
+	}
+
+	return f
+}
+
+// getRequiredRule returns name of the rule that makes value required, for a failure of it
+func (v *ValueValidation) getRequiredRule() string {
+	if v.Flags&NotBlank > 0 {
+		return "notblank"
+	}
+	if v.Flags&ReqTrim > 0 {
+		return "reqtrim"
+	}
+	return "req"
+}
+
+// isInRanges checks if value of an int field is in any of the Ranges, bounds included.  Unsigned value greater than
+// math.MaxInt64 is never in a range.
+func (v *ValueValidation) isInRanges(value reflect.Value) bool {
+	var i int64
+	if isUint(value.Kind()) {
+		if value.Uint() > math.MaxInt64 {
+			return false
+		}
+		i = int64(value.Uint())
+	} else {
+		i = value.Int()
+	}
+
+	for _, r := range v.Ranges {
+		if i >= r[0] && i <= r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// isMultipleOf checks if a number is a multiple of n.  Ints are checked with modulo when n is an int.  Otherwise
+// floats are compared with a tolerance: value divided by n cannot differ from an integer by more than 1e-9, so that
+// eg. 0.3 is a multiple of 0.1 despite the floating point error.
+func isMultipleOf(value reflect.Value, n float64) bool {
+	isIntN := n == math.Trunc(n) && math.Abs(n) < 1<<53
+	switch {
+	case isSignedInt(value.Kind()) && isIntN:
+		return value.Int()%int64(n) == 0
+	case isUint(value.Kind()) && isIntN:
+		return value.Uint()%uint64(math.Abs(n)) == 0
+	}
+	q := toFloat(value) / n
+	return math.Abs(q-math.Round(q)) <= 1e-9
+}
+
+// getSign returns -1, 0 or 1 when a number is negative, zero or positive
+func getSign(value reflect.Value) int {
+	switch {
+	case isSignedInt(value.Kind()):
+		return cmpOrdered(value.Int(), 0)
+	case isUint(value.Kind()):
+		return cmpOrdered(value.Uint(), 0)
+	}
+	return cmpOrdered(value.Float(), 0)
+}
+
+// hasRange checks if valmin or valmax is set for a number of kind k
+func (v *ValueValidation) hasRange(k reflect.Kind) bool {
+	if v.Flags&(ValMinNotNil|ValMaxNotNil|Positive|NonNegative|Negative|NonPositive|Gt|Gte|Lt|Lte) > 0 {
+		return true
+	}
+	if isFloat(k) {
+		return v.ValMinFloat != 0 || v.ValMaxFloat != 0
+	}
+	if isUint(k) && (v.ValMinUint != 0 || v.ValMaxUint != 0) {
+		return true
+	}
+	return v.ValMin != 0 || v.ValMax != 0
+}
+
+// appliesTo checks if rules can be checked against a value of kind k.  Rules of strings, numbers and bools apply to
+// values of that kind only, while other rules, such as req, lenmin or oneof, apply to any kind.
+func (v *ValueValidation) appliesTo(k reflect.Kind) bool {
+	stringRules := v.Regexp != nil || v.RegexpNot != nil || v.Prefix != "" || v.Suffix != "" || len(v.Contains) > 0 ||
+		len(v.Excludes) > 0 || v.Charset != "" || v.CharsetNot != "" || v.DateTime != "" || v.Decimal > -1 ||
+		v.Flags&(Email|URL|UUID|UUIDv4|IP|IPv4|IPv6|CIDR|Base64|Base64URL|Hex|JSON|CreditCard|Hostname|FQDN|SemVer|
+			Alpha|Numeric|Alphanumeric|Lowercase|Uppercase|NumericRange|ReqTrim|NotBlank) > 0
+	if stringRules && k != reflect.String {
+		return false
+	}
+	// with numericrange, valmin and valmax apply to a string
+	numberRules := (v.Flags&NumericRange == 0 && v.hasRange(reflect.Float64)) || v.MultipleOf != 0 || v.hasIntRules()
+	if numberRules && !isInt(k) && !isFloat(k) {
+		return false
+	}
+	if v.Flags&(IsTrue|IsFalse) > 0 && k != reflect.Bool {
+		return false
+	}
+	if v.Flags&PercentFloat > 0 && !isFloat(k) {
+		return false
+	}
+	return true
+}
+
+// hasIntRules checks if any of the rules that apply to ints only is set
+func (v *ValueValidation) hasIntRules() bool {
+	return len(v.Ranges) > 0 || len(v.OneOfInt) > 0 || v.DigitsMin > -1 || v.DigitsMax > -1 || v.Digits > -1
+}
+
+// isOneOf checks if value is one of the allowed values.  For numbers, allowed values are parsed with strconv.  With
+// OneOfCI flag, strings are compared case-insensitively.
+func (v *ValueValidation) isOneOf(value reflect.Value) bool {
+	for _, allowed := range v.OneOf {
+		switch {
+		case value.Kind() == reflect.String:
+			if value.String() == allowed || (v.Flags&OneOfCI > 0 && strings.EqualFold(value.String(), allowed)) {
+				return true
+			}
+		case isSignedInt(value.Kind()):
+			i, err := strconv.ParseInt(allowed, 10, 64)
+			if err == nil && value.Int() == i {
+				return true
+			}
+		case isUint(value.Kind()):
+			i, err := strconv.ParseUint(allowed, 10, 64)
+			if err == nil && value.Uint() == i {
+				return true
+			}
+		case isFloat(value.Kind()):
+			f, err := strconv.ParseFloat(allowed, 64)
+			if err == nil && value.Float() == f {
+				return true
+			}
+		default:
+			// other kinds cannot be compared so oneof rule is ignored
+			return true
+		}
+	}
+	return false
+}
+
+// isOneOfInt checks if value of an int field is one of the values in OneOfInt.  Signed and unsigned ints are compared
+// the same way, and unsigned value greater than math.MaxInt64 is never allowed.
+func (v *ValueValidation) isOneOfInt(value reflect.Value) bool {
+	var i int64
+	if isUint(value.Kind()) {
+		if value.Uint() > math.MaxInt64 {
+			return false
+		}
+		i = int64(value.Uint())
+	} else {
+		i = value.Int()
+	}
+
+	for _, allowed := range v.OneOfInt {
+		if i == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// isURL checks if string is an absolute URL with a scheme
+func isURL(s string) bool {
+	u, err := url.ParseRequestURI(s)
+	if err != nil {
+		return false
+	}
+	return u.Scheme != ""
+}
+
+// isIP checks if string is an IP address, from the family set with IPv4 or IPv6 flag.  IPv4-mapped IPv6 addresses,
+// eg. "::ffff:10.0.0.1", are IPv6 ones.
+func (v *ValueValidation) isIP(s string) bool {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return false
+	}
+	if v.Flags&IPv4 > 0 {
+		return !strings.Contains(s, ":")
+	}
+	if v.Flags&IPv6 > 0 {
+		return strings.Contains(s, ":")
+	}
+	return true
+}
+
+// isBase64 checks if string is encoded with base64 encoding, with correct padding
+func isBase64(s string, encoding *base64.Encoding) bool {
+	_, err := encoding.Strict().DecodeString(s)
+	return err == nil
+}
+
+// isHex checks if string is hex-encoded, so it has an even number of hexadecimal digits
+func isHex(s string) bool {
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+// isDecimal checks if string is a decimal number with at most scale fractional digits, eg. "-19.99".  Integer part
+// cannot have leading zeros, and when there is a dot it has to be followed by at least one digit.
+func isDecimal(s string, scale int) bool {
+	s = strings.TrimPrefix(s, "-")
+	integer, fraction, hasFraction := strings.Cut(s, ".")
+	if integer == "" || !numericRegex.MatchString(integer) || (len(integer) > 1 && integer[0] == '0') {
+		return false
+	}
+	if !hasFraction {
+		return true
+	}
+	return fraction != "" && len(fraction) <= scale && numericRegex.MatchString(fraction)
+}
+
+// isCreditCard checks if string, without spaces and dashes, has 12 to 19 digits and a valid Luhn checksum
+func isCreditCard(s string) bool {
+	digits := strings.NewReplacer(" ", "", "-", "").Replace(s)
+	if len(digits) < 12 || len(digits) > 19 {
+		return false
+	}
+
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		if digits[i] < '0' || digits[i] > '9' {
+			return false
+		}
+		d := int(digits[i] - '0')
+		// every second digit from the right is doubled
+		if (len(digits)-i)%2 == 0 {
+			d = d * 2
+			if d > 9 {
+				d = d - 9
+			}
+		}
+		sum = sum + d
+	}
+	return sum%10 == 0
+}
+
+// isSemVer checks if string is a semantic version, eg. "1.2.3-rc.1+build".  When withPrefix is true then a leading
+// "v" is allowed, eg. "v1.2.3".
+func isSemVer(s string, withPrefix bool) bool {
+	if withPrefix {
+		s = strings.TrimPrefix(s, "v")
+	}
+	return semverRegex.MatchString(s)
+}
+
+func isDateTime(s string, layout string) bool {
+	_, err := time.Parse(layout, s)
+	return err == nil
+}
+
+// isCIDR checks if string is an IP address with a prefix length, eg. "10.0.0.0/8"
+func isCIDR(s string) bool {
+	_, _, err := net.ParseCIDR(s)
+	return err == nil
 }
 
 func NewValueValidation() *ValueValidation {
 	return &ValueValidation{
-		LenMin: -1,
-		LenMax: -1,
+		LenMin:    -1,
+		LenMax:    -1,
+		Len:       -1,
+		DigitsMin: -1,
+		DigitsMax: -1,
+		Digits:    -1,
+		Decimal:   -1,
 	}
 }
+
+// isEmpty checks if there are no rules in validation, eg. when a field has no tags
+func (v *ValueValidation) isEmpty() bool {
+	return reflect.DeepEqual(v, NewValueValidation())
+}